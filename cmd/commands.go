@@ -0,0 +1,393 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"portfolio-backend/configs"
+	"portfolio-backend/internal/database"
+	"portfolio-backend/internal/migrations"
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/services"
+	"portfolio-backend/internal/storage"
+	"portfolio-backend/internal/store"
+)
+
+const usage = `Usage: portfolio-backend [command]
+
+Without a command the HTTP server is started.
+
+Commands:
+  schema check           Report differences between the database and the
+                         expected indexes and validators; exits 1 when
+                         changes are pending
+  schema apply           Create missing collections and indexes and update
+                         validators
+  migrate status         List migrations and whether they are applied
+  migrate up [version]   Apply pending migrations, optionally up to version
+  migrate down [steps]   Revert the last applied migrations (default 1)
+  seed                   Apply the schema and insert sample projects into an
+                         empty projects collection
+  media variants         Generate the resized variants and placeholders of
+                         media uploaded before they existed
+  users create <username> <role> [email]
+                         Create a user with the role owner, editor or
+                         viewer; the password is read from standard input
+  users set-role <username> <role>
+                         Change the role of a user and revoke the access
+                         tokens issued with the old one
+`
+
+// runCommand runs an administrative command instead of the server and
+// returns the process exit code.
+func runCommand(config *configs.Config, args []string) int {
+	switch {
+	case len(args) == 2 && args[0] == "schema" && (args[1] == "check" || args[1] == "apply"):
+		return schemaCommand(config, args[1] == "apply")
+	case len(args) >= 2 && len(args) <= 3 && args[0] == "migrate":
+		return migrateCommand(config, args[1], args[2:])
+	case len(args) == 1 && args[0] == "seed":
+		return seedCommand(config)
+	case len(args) == 2 && args[0] == "media" && args[1] == "variants":
+		return mediaVariantsCommand(config)
+	case len(args) >= 2 && args[0] == "users":
+		return usersCommand(config, args[1], args[2:])
+	case args[0] == "help" || args[0] == "-h" || args[0] == "--help":
+		fmt.Print(usage)
+		return 0
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+}
+
+func schemaCommand(config *configs.Config, apply bool) int {
+	db, err := connectMongoDB(config)
+	if err != nil {
+		log.Println(err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	changes, err := store.EnsureSchema(ctx, db, apply)
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if err != nil {
+		log.Println("Schema update failed:", err)
+		return 1
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("Schema is up to date")
+	}
+	if !apply && hasPendingChanges(changes) {
+		return 1
+	}
+	return 0
+}
+
+func migrateCommand(config *configs.Config, action string, args []string) int {
+	var n int64
+	switch {
+	case action != "status" && action != "up" && action != "down",
+		action == "status" && len(args) > 0:
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	case len(args) == 1:
+		var err error
+		if n, err = strconv.ParseInt(args[0], 10, 64); err != nil || n < 0 {
+			fmt.Fprint(os.Stderr, usage)
+			return 2
+		}
+	}
+
+	db, err := connectMongoDB(config)
+	if err != nil {
+		log.Println(err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	migrator := migrations.New(db.Database)
+	var done []int64
+	verb := "Applied"
+	switch action {
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Println(err)
+			return 1
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%4d  %-28s  %s\n", status.Version, applied, status.Description)
+		}
+		return 0
+	case "up":
+		done, err = migrator.Up(ctx, n)
+	case "down":
+		if n == 0 {
+			n = 1
+		}
+		verb = "Reverted"
+		done, err = migrator.Down(ctx, int(n))
+	}
+
+	for _, version := range done {
+		fmt.Printf("%s %d\n", verb, version)
+	}
+	if err != nil {
+		log.Println("Migration failed:", err)
+		return 1
+	}
+	if len(done) == 0 {
+		fmt.Println("Nothing to migrate")
+	}
+	return 0
+}
+
+func seedCommand(config *configs.Config) int {
+	if code := schemaCommand(config, true); code != 0 {
+		return code
+	}
+
+	st, err := store.New(config)
+	if err != nil {
+		log.Println(err)
+		return 1
+	}
+	defer st.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	count, err := st.Projects.Count(ctx, store.ProjectQuery{})
+	if err != nil {
+		log.Println(err)
+		return 1
+	}
+	if count > 0 {
+		fmt.Printf("Projects collection already has %d projects; nothing to seed\n", count)
+		return 0
+	}
+
+	// Going through the service validates the samples and records their
+	// first revisions. The samples have no media, so no file storage is
+	// needed.
+	mediaService := services.NewMediaService(st.Media, st.Projects, st.Posts, nil, 0, "")
+	projectService := services.NewProjectService(st.Projects, st.ProjectRevisions, mediaService)
+	for i := range sampleProjects {
+		project := sampleProjects[i]
+		if err := projectService.CreateProject(&project, "seed"); err != nil {
+			log.Println("Failed to insert sample project:", err)
+			return 1
+		}
+	}
+	fmt.Printf("Inserted %d sample projects\n", len(sampleProjects))
+	return 0
+}
+
+// mediaVariantsCommand generates the variants of media uploaded without them.
+func mediaVariantsCommand(config *configs.Config) int {
+	st, err := store.New(config)
+	if err != nil {
+		log.Println(err)
+		return 1
+	}
+	defer st.Close()
+
+	mediaStorage, err := storage.New(config.MediaStorage, config.MediaDir)
+	if err != nil {
+		log.Println(err)
+		return 1
+	}
+
+	mediaService := services.NewMediaService(
+		st.Media,
+		st.Projects,
+		st.Posts,
+		mediaStorage,
+		int64(config.MediaMaxUploadSize),
+		config.MediaPublicURL,
+	)
+	updated, err := mediaService.GenerateMissingVariants(context.Background())
+	fmt.Printf("Generated variants for %d media\n", updated)
+	if err != nil {
+		log.Println(err)
+		return 1
+	}
+	return 0
+}
+
+// usersCommand creates users and changes their roles, so editors and
+// viewers can be added next to the bootstrap owner.
+func usersCommand(config *configs.Config, action string, args []string) int {
+	valid := action == "create" && (len(args) == 2 || len(args) == 3) ||
+		action == "set-role" && len(args) == 2
+	if !valid {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	role := models.Role(args[1])
+	if !role.Valid() {
+		log.Printf("Unknown role %q; use owner, editor or viewer", role)
+		return 2
+	}
+
+	var password string
+	if action == "create" {
+		var err error
+		if password, err = readPassword(os.Stdin); err != nil {
+			log.Println("Failed to read the password:", err)
+			return 1
+		}
+		if err := services.ValidatePasswordStrength(password, args[0]); err != nil {
+			log.Println(err)
+			return 1
+		}
+	}
+
+	st, err := store.New(config)
+	if err != nil {
+		log.Println(err)
+		return 1
+	}
+	defer st.Close()
+
+	userService := services.NewUserService(st.Users)
+	if action == "create" {
+		var email string
+		if len(args) == 3 {
+			email = args[2]
+		}
+		user, err := userService.CreateUser(args[0], password, "", email, role)
+		if err != nil {
+			log.Println("Failed to create user:", err)
+			return 1
+		}
+		fmt.Printf("Created %s %q\n", user.Role, user.Username)
+		return 0
+	}
+
+	user, err := userService.SetRole(args[0], role)
+	if errors.Is(err, store.ErrNotFound) {
+		log.Printf("User %q not found", args[0])
+		return 1
+	}
+	if err != nil {
+		log.Println("Failed to change role:", err)
+		return 1
+	}
+	fmt.Printf("%q is now %s\n", user.Username, user.Role)
+	return 0
+}
+
+// readPassword reads the first line of r, so passwords can be piped in
+// rather than passed on the command line.
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("no password given on standard input")
+	}
+	return password, nil
+}
+
+// connectMongoDB connects for commands that only make sense against MongoDB.
+func connectMongoDB(config *configs.Config) (*database.MongoDB, error) {
+	if config.StorageBackend != store.BackendMongoDB && config.StorageBackend != "" {
+		return nil, fmt.Errorf("this command requires STORAGE_BACKEND=%s", store.BackendMongoDB)
+	}
+	return database.NewMongoDB(config.MongoDBURI, config.MongoDBDatabase)
+}
+
+// ensureMigrations handles pending migrations on startup according to
+// MONGODB_MIGRATIONS: apply (default), check or off. Data written before a
+// migration, such as projects without a status, is otherwise served as is.
+func ensureMigrations(db *database.MongoDB, mode string) {
+	if mode == "off" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	migrator := migrations.New(db.Database)
+	if mode == "check" {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			log.Println("Failed to check migrations:", err)
+			return
+		}
+		if len(pending) > 0 {
+			log.Printf("%d pending migrations; run `portfolio-backend migrate up`", len(pending))
+		}
+		return
+	}
+
+	done, err := migrator.Up(ctx, 0)
+	for _, version := range done {
+		log.Println("Applied migration", version)
+	}
+	if errors.Is(err, migrations.ErrLocked) {
+		log.Println("Migrations are being applied by another instance")
+		return
+	}
+	if err != nil {
+		log.Fatal("Failed to apply migrations:", err)
+	}
+}
+
+// ensureSchema reconciles the database schema on startup according to
+// MONGODB_SCHEMA: apply (default), check or off.
+func ensureSchema(db *database.MongoDB, mode string) {
+	if mode == "off" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	apply := mode != "check"
+	changes, err := store.EnsureSchema(ctx, db, apply)
+	for _, change := range changes {
+		log.Println("Schema:", change)
+	}
+	if err != nil {
+		log.Fatal("Failed to ensure database schema:", err)
+	}
+	if !apply && hasPendingChanges(changes) {
+		log.Println("Schema drift detected; run `portfolio-backend schema apply` to fix it")
+	}
+}
+
+func hasPendingChanges(changes []store.SchemaChange) bool {
+	for _, change := range changes {
+		if change.Action != store.SchemaUnexpected && !change.Applied {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"portfolio-backend/configs"
+	"portfolio-backend/internal/store"
+)
+
+func TestReadPassword(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Sup3r-Secret-Pass!\n", "Sup3r-Secret-Pass!"},
+		{"Sup3r-Secret-Pass!\r\nignored\n", "Sup3r-Secret-Pass!"},
+		{"Sup3r-Secret-Pass!", "Sup3r-Secret-Pass!"},
+		{" spaced out \n", " spaced out "},
+	}
+	for _, tt := range tests {
+		if got, err := readPassword(strings.NewReader(tt.in)); err != nil || got != tt.want {
+			t.Errorf("readPassword(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "\n"} {
+		if _, err := readPassword(strings.NewReader(in)); err == nil {
+			t.Errorf("readPassword(%q) accepted an empty password", in)
+		}
+	}
+}
+
+func TestUsersCommandRejectsBadArguments(t *testing.T) {
+	config := &configs.Config{StorageBackend: store.BackendMemory}
+	for _, args := range [][]string{
+		{"users", "create", "alice"},
+		{"users", "create", "alice", "editor", "alice@example.com", "extra"},
+		{"users", "set-role", "alice"},
+		{"users", "delete", "alice"},
+		{"users", "set-role", "alice", "admin"},
+	} {
+		if code := runCommand(config, args); code != 2 {
+			t.Errorf("runCommand(%q) = %d, want 2", args, code)
+		}
+	}
+}
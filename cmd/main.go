@@ -1,32 +1,61 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"portfolio-backend/configs"
-	"portfolio-backend/internal/database"
 	"portfolio-backend/internal/handlers"
 	"portfolio-backend/internal/middleware"
+	"portfolio-backend/internal/models"
 	"portfolio-backend/internal/services"
+	"portfolio-backend/internal/storage"
+	"portfolio-backend/internal/store"
 )
 
 func main() {
 	// Load configuration
 	config := configs.LoadConfig()
 
+	// Administrative commands, e.g. `portfolio-backend schema check`
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(config, os.Args[1:]))
+	}
+
 	// Set Gin mode
 	gin.SetMode(config.GinMode)
 
-	// Initialize database
-	db, err := database.NewMongoDB(config.MongoDBURI, config.MongoDBDatabase)
+	// Load JWT signing keys
+	jwtKeys, err := loadJWTKeys(config)
+	if err != nil {
+		log.Fatal("Failed to load JWT keys:", err)
+	}
+
+	// Initialize storage backend
+	st, err := store.New(config)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
-	defer db.Close()
+	defer st.Close()
+
+	// Ensure indexes and validators, and apply pending migrations
+	if db := st.DB(); db != nil {
+		ensureSchema(db, config.MongoDBSchema)
+		ensureMigrations(db, config.MongoDBMigrations)
+	}
+
+	// Initialize media file storage
+	mediaStorage, err := storage.New(config.MediaStorage, config.MediaDir)
+	if err != nil {
+		log.Fatal("Failed to initialize media storage:", err)
+	}
 
 	// Initialize email service
 	emailService := services.NewEmailService(
@@ -37,13 +66,77 @@ func main() {
 	)
 
 	// Initialize services
-	contactService := services.NewContactService(db, emailService)
-	projectService := services.NewProjectService(db)
+	contactService := services.NewContactService(st.Contacts, emailService)
+	mediaService := services.NewMediaService(
+		st.Media,
+		st.Projects,
+		st.Posts,
+		mediaStorage,
+		int64(config.MediaMaxUploadSize),
+		config.MediaPublicURL,
+	)
+	projectService := services.NewProjectService(st.Projects, st.ProjectRevisions, mediaService)
+	postService := services.NewPostService(st.Posts, mediaService)
+	userService := services.NewUserService(st.Users)
+	tokenService := services.NewTokenService(
+		st.RefreshTokens,
+		st.RevokedTokens,
+		st.Users,
+		jwtKeys,
+		configs.ParseDuration(config.JWTExpiry, 15*time.Minute),
+		configs.ParseDuration(config.RefreshExpiry, 30*24*time.Hour),
+	)
+	mfaService := services.NewMFAService(st.Users, config.MFAIssuer)
+	apiKeyService := services.NewAPIKeyService(st.APIKeys)
+	loginAuditService := services.NewLoginAuditService(st.LoginEvents)
+	passwordResetService := services.NewPasswordResetService(
+		st.ResetTokens,
+		st.Users,
+		userService,
+		tokenService,
+		emailService,
+		config.PasswordResetURL,
+		configs.ParseDuration(config.PasswordResetExpiry, time.Hour),
+	)
+	loginGuard := services.NewLoginGuard(
+		config.LoginMaxAttempts,
+		config.LoginIPMaxAttempts,
+		configs.ParseDuration(config.LoginLockout, 15*time.Minute),
+	)
+
+	// Permanently delete items that have been in the trash too long
+	trashPurger := services.NewTrashPurger(
+		st.Projects,
+		st.Contacts,
+		configs.ParseDuration(config.TrashRetention, 30*24*time.Hour),
+		configs.ParseDuration(config.TrashPurgeInterval, time.Hour),
+	)
+	go trashPurger.Run(context.Background())
+
+	// Publish and archive projects at their scheduled times
+	projectScheduler := services.NewProjectScheduler(
+		projectService,
+		configs.ParseDuration(config.SchedulerInterval, time.Minute),
+	)
+	go projectScheduler.Run(context.Background())
+
+	// Create the first admin user from the environment if none exist yet
+	if err := userService.EnsureBootstrapAdmin(config.AdminUsername, config.AdminPassword, config.AdminDisplayName, config.AdminEmail); err != nil {
+		log.Fatal("Failed to bootstrap admin user:", err)
+	}
 
 	// Initialize handlers
 	contactHandler := handlers.NewContactHandler(contactService)
 	projectHandler := handlers.NewProjectHandler(projectService)
-	authHandler := handlers.NewAuthHandler(config)
+	postHandler := handlers.NewPostHandler(postService)
+	mediaHandler := handlers.NewMediaHandler(mediaService)
+	authHandler := handlers.NewAuthHandler(userService, tokenService, mfaService, loginGuard, loginAuditService)
+	mfaHandler := handlers.NewMFAHandler(mfaService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	passwordHandler := handlers.NewPasswordHandler(userService, tokenService, passwordResetService)
+
+	// Initialize auth middleware
+	authMiddleware := middleware.AuthMiddleware(jwtKeys, tokenService, apiKeyService)
 
 	// Initialize rate limiter
 	rateLimiter := middleware.NewRateLimiter(10, time.Minute) // 10 requests per minute
@@ -51,6 +144,12 @@ func main() {
 	// Initialize router
 	router := gin.New()
 
+	// Only honor X-Forwarded-For from configured proxies, so clients cannot
+	// pick the IP that rate limiting and login throttling are keyed on
+	if err := router.SetTrustedProxies(trustedProxies(config)); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+
 	// Add middleware
 	router.Use(middleware.LoggingMiddleware())
 	router.Use(middleware.CORSMiddleware(config.AllowedOrigins))
@@ -65,6 +164,11 @@ func main() {
 		})
 	})
 
+	// Public keys for verifying access tokens
+	router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, jwtKeys.JWKS())
+	})
+
 	// API routes
 	api := router.Group("/api/v1")
 	{
@@ -72,27 +176,96 @@ func main() {
 		auth := api.Group("/auth")
 		{
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/login/mfa", authHandler.LoginMFA)
+			auth.POST("/logout", authMiddleware, authHandler.Logout)
+
+			auth.PUT("/password", authMiddleware, passwordHandler.ChangePassword)
+			auth.POST("/password/forgot", rateLimiter.RateLimitMiddleware(), passwordHandler.ForgotPassword)
+			auth.POST("/password/reset", rateLimiter.RateLimitMiddleware(), passwordHandler.ResetPassword)
+			auth.GET("/login-events", authMiddleware, middleware.RequirePermission(models.PermissionUsersManage), authHandler.GetLoginEvents)
+
+			// Two-factor authentication management for the current user
+			auth.POST("/mfa/totp/enroll", authMiddleware, mfaHandler.EnrollTOTP)
+			auth.POST("/mfa/totp/confirm", authMiddleware, mfaHandler.ConfirmTOTP)
+			auth.POST("/mfa/totp/disable", authMiddleware, mfaHandler.DisableTOTP)
+		}
+
+		// API key management
+		apiKeys := api.Group("/api-keys", authMiddleware, middleware.RequirePermission(models.PermissionUsersManage))
+		{
+			apiKeys.POST("/", apiKeyHandler.CreateAPIKey)
+			apiKeys.GET("/", apiKeyHandler.GetAllAPIKeys)
+			apiKeys.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
 		}
 
 		// Contact routes
 		contacts := api.Group("/contacts")
 		{
 			contacts.POST("/", rateLimiter.RateLimitMiddleware(), contactHandler.CreateContact)
-			contacts.GET("/", middleware.AuthMiddleware(config.JWTSecret), contactHandler.GetAllContacts)
-			contacts.GET("/:id", middleware.AuthMiddleware(config.JWTSecret), contactHandler.GetContactByID)
-			contacts.PUT("/:id/read", middleware.AuthMiddleware(config.JWTSecret), contactHandler.MarkAsRead)
-			contacts.DELETE("/:id", middleware.AuthMiddleware(config.JWTSecret), contactHandler.DeleteContact)
+			contacts.GET("/", authMiddleware, middleware.RequirePermission(models.PermissionContactsRead), contactHandler.GetAllContacts)
+			contacts.GET("/search", authMiddleware, middleware.RequirePermission(models.PermissionContactsRead), contactHandler.SearchContacts)
+			contacts.GET("/trash", authMiddleware, middleware.RequirePermission(models.PermissionContactsDelete), contactHandler.GetTrashedContacts)
+			contacts.POST("/trash/:id/restore", authMiddleware, middleware.RequirePermission(models.PermissionContactsDelete), contactHandler.RestoreContact)
+			contacts.DELETE("/trash/:id", authMiddleware, middleware.RequirePermission(models.PermissionContactsDelete), contactHandler.PurgeContact)
+			contacts.GET("/:id", authMiddleware, middleware.RequirePermission(models.PermissionContactsRead), contactHandler.GetContactByID)
+			contacts.PUT("/:id/read", authMiddleware, middleware.RequirePermission(models.PermissionContactsWrite), contactHandler.MarkAsRead)
+			contacts.DELETE("/:id", authMiddleware, middleware.RequirePermission(models.PermissionContactsDelete), contactHandler.DeleteContact)
 		}
 
 		// Project routes
 		projects := api.Group("/projects")
 		{
-			projects.POST("/", middleware.AuthMiddleware(config.JWTSecret), projectHandler.CreateProject)
+			projects.POST("/", authMiddleware, middleware.RequirePermission(models.PermissionProjectsWrite), projectHandler.CreateProject)
 			projects.GET("/", projectHandler.GetAllProjects)
 			projects.GET("/featured", projectHandler.GetFeaturedProjects)
+			projects.GET("/search", projectHandler.SearchProjects)
+			projects.GET("/by-slug/:slug", projectHandler.GetProjectBySlug)
+			projects.PUT("/order", authMiddleware, middleware.RequirePermission(models.PermissionProjectsWrite), projectHandler.ReorderProjects)
+			projects.GET("/trash", authMiddleware, middleware.RequirePermission(models.PermissionProjectsWrite), projectHandler.GetTrashedProjects)
+			projects.POST("/trash/:id/restore", authMiddleware, middleware.RequirePermission(models.PermissionProjectsWrite), projectHandler.RestoreProject)
+			projects.DELETE("/trash/:id", authMiddleware, middleware.RequirePermission(models.PermissionProjectsWrite), projectHandler.PurgeProject)
 			projects.GET("/:id", projectHandler.GetProjectByID)
-			projects.PUT("/:id", middleware.AuthMiddleware(config.JWTSecret), projectHandler.UpdateProject)
-			projects.DELETE("/:id", middleware.AuthMiddleware(config.JWTSecret), projectHandler.DeleteProject)
+			projects.PUT("/:id", authMiddleware, middleware.RequirePermission(models.PermissionProjectsWrite), projectHandler.UpdateProject)
+			projects.PATCH("/:id", authMiddleware, middleware.RequirePermission(models.PermissionProjectsWrite), projectHandler.PatchProject)
+			projects.DELETE("/:id", authMiddleware, middleware.RequirePermission(models.PermissionProjectsWrite), projectHandler.DeleteProject)
+			projects.GET("/:id/revisions", authMiddleware, middleware.RequirePermission(models.PermissionProjectsRead), projectHandler.GetProjectRevisions)
+			projects.GET("/:id/revisions/diff", authMiddleware, middleware.RequirePermission(models.PermissionProjectsRead), projectHandler.DiffProjectRevisions)
+			projects.GET("/:id/revisions/:number", authMiddleware, middleware.RequirePermission(models.PermissionProjectsRead), projectHandler.GetProjectRevision)
+			projects.POST("/:id/revisions/:number/restore", authMiddleware, middleware.RequirePermission(models.PermissionProjectsWrite), projectHandler.RestoreProjectRevision)
+		}
+
+		// Blog post routes
+		posts := api.Group("/posts")
+		{
+			posts.POST("/", authMiddleware, middleware.RequirePermission(models.PermissionPostsWrite), postHandler.CreatePost)
+			posts.GET("/", postHandler.GetAllPosts)
+			posts.GET("/tags", postHandler.GetPostTags)
+			posts.GET("/tags/:tag", postHandler.GetPostsByTag)
+			posts.GET("/by-slug/:slug", postHandler.GetPostBySlug)
+			posts.GET("/:id", postHandler.GetPostByID)
+			posts.PUT("/:id", authMiddleware, middleware.RequirePermission(models.PermissionPostsWrite), postHandler.UpdatePost)
+			posts.DELETE("/:id", authMiddleware, middleware.RequirePermission(models.PermissionPostsWrite), postHandler.DeletePost)
+		}
+
+		// Media library for project and post images
+		media := api.Group("/media")
+		{
+			media.POST("/", authMiddleware, middleware.RequirePermission(models.PermissionProjectsWrite), mediaHandler.UploadMedia)
+			media.GET("/", authMiddleware, middleware.RequirePermission(models.PermissionProjectsRead), mediaHandler.GetMedia)
+			media.GET("/:id", authMiddleware, middleware.RequirePermission(models.PermissionProjectsRead), mediaHandler.GetMediaByID)
+			media.GET("/:id/file", mediaHandler.GetMediaFile)
+			media.GET("/:id/variants/:name", mediaHandler.GetMediaVariant)
+			media.DELETE("/:id", authMiddleware, middleware.RequirePermission(models.PermissionProjectsWrite), mediaHandler.DeleteMedia)
+		}
+
+		// Admin views that include unpublished content
+		admin := api.Group("/admin", authMiddleware)
+		{
+			admin.GET("/projects", middleware.RequirePermission(models.PermissionProjectsRead), projectHandler.GetAllProjectsAdmin)
+			admin.GET("/projects/:id", middleware.RequirePermission(models.PermissionProjectsRead), projectHandler.GetProjectByIDAdmin)
+			admin.GET("/posts", middleware.RequirePermission(models.PermissionPostsRead), postHandler.GetAllPostsAdmin)
+			admin.GET("/posts/:id", middleware.RequirePermission(models.PermissionPostsRead), postHandler.GetPostByIDAdmin)
 		}
 	}
 
@@ -102,3 +275,28 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// loadJWTKeys returns the key set from JWT_KEYS_DIR, or a single HS256 key
+// from JWT_SECRET when no key directory is configured.
+func loadJWTKeys(config *configs.Config) (*middleware.KeySet, error) {
+	if config.JWTKeysDir != "" {
+		return middleware.LoadKeySet(config.JWTKeysDir, config.JWTActiveKID)
+	}
+
+	if config.GinMode == gin.ReleaseMode && config.JWTSecret == configs.DefaultJWTSecret {
+		return nil, errors.New("refusing to use the default JWT_SECRET in release mode; set JWT_SECRET or JWT_KEYS_DIR")
+	}
+	return middleware.NewHMACKeySet(config.JWTSecret), nil
+}
+
+// trustedProxies returns the addresses and CIDR ranges listed in
+// TRUSTED_PROXIES, or nil to trust no proxy headers at all.
+func trustedProxies(config *configs.Config) []string {
+	var proxies []string
+	for _, proxy := range strings.Split(config.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
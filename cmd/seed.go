@@ -0,0 +1,52 @@
+package main
+
+import "portfolio-backend/internal/models"
+
+// sampleProjects are inserted by `portfolio-backend seed` into an empty
+// projects collection.
+var sampleProjects = []models.Project{
+	{
+		Title:        "E-Commerce Platform",
+		Description:  "A full-stack e-commerce platform built with React, Node.js, and MongoDB. Features include user authentication, product management, shopping cart, and payment integration.",
+		ImageURL:     "https://via.placeholder.com/600x400/2563eb/ffffff?text=E-Commerce",
+		LiveURL:      "https://example-ecommerce.com",
+		GitHubURL:    "https://github.com/username/ecommerce-platform",
+		Technologies: []string{"React", "Node.js", "MongoDB", "Express", "Stripe"},
+		Category:     "Web Development",
+		Featured:     true,
+		Status:       models.ProjectStatusPublished,
+	},
+	{
+		Title:        "Task Management App",
+		Description:  "A collaborative task management application with real-time updates, drag-and-drop functionality, and team collaboration features.",
+		ImageURL:     "https://via.placeholder.com/600x400/7c3aed/ffffff?text=Task+Manager",
+		LiveURL:      "https://example-taskmanager.com",
+		GitHubURL:    "https://github.com/username/task-manager",
+		Technologies: []string{"Vue.js", "Firebase", "Vuex", "Vuetify"},
+		Category:     "Web Development",
+		Featured:     true,
+		Status:       models.ProjectStatusPublished,
+	},
+	{
+		Title:        "Weather Dashboard",
+		Description:  "A weather dashboard that displays current weather conditions and forecasts using data from multiple weather APIs.",
+		ImageURL:     "https://via.placeholder.com/600x400/059669/ffffff?text=Weather+App",
+		LiveURL:      "https://example-weather.com",
+		GitHubURL:    "https://github.com/username/weather-dashboard",
+		Technologies: []string{"JavaScript", "HTML5", "CSS3", "OpenWeather API"},
+		Category:     "Web Development",
+		Featured:     false,
+		Status:       models.ProjectStatusPublished,
+	},
+	{
+		Title:        "Portfolio Website",
+		Description:  "A responsive portfolio website showcasing projects and skills with modern design and smooth animations.",
+		ImageURL:     "https://via.placeholder.com/600x400/dc2626/ffffff?text=Portfolio",
+		LiveURL:      "https://example-portfolio.com",
+		GitHubURL:    "https://github.com/username/portfolio",
+		Technologies: []string{"React", "Tailwind CSS", "Framer Motion", "Vite"},
+		Category:     "Web Development",
+		Featured:     true,
+		Status:       models.ProjectStatusPublished,
+	},
+}
@@ -3,22 +3,52 @@ package configs
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// DefaultJWTSecret is the placeholder secret used when JWT_SECRET is unset.
+// The server refuses to start with it in release mode.
+const DefaultJWTSecret = "your-super-secret-jwt-key-here"
+
 type Config struct {
-	Port            string
-	GinMode         string
-	MongoDBURI      string
-	MongoDBDatabase string
-	JWTSecret       string
-	JWTExpiry       string
-	AllowedOrigins  string
-	SMTPHost        string
-	SMTPPort        string
-	SMTPUsername    string
-	SMTPPassword    string
+	Port                string
+	GinMode             string
+	MongoDBURI          string
+	MongoDBDatabase     string
+	StorageBackend      string
+	MongoDBSchema       string
+	MongoDBMigrations   string
+	JWTSecret           string
+	JWTKeysDir          string
+	JWTActiveKID        string
+	JWTExpiry           string
+	RefreshExpiry       string
+	AllowedOrigins      string
+	TrustedProxies      string
+	SMTPHost            string
+	SMTPPort            string
+	SMTPUsername        string
+	SMTPPassword        string
+	AdminUsername       string
+	AdminPassword       string
+	AdminDisplayName    string
+	AdminEmail          string
+	MFAIssuer           string
+	LoginMaxAttempts    int
+	LoginIPMaxAttempts  int
+	LoginLockout        string
+	PasswordResetURL    string
+	PasswordResetExpiry string
+	TrashRetention      string
+	TrashPurgeInterval  string
+	SchedulerInterval   string
+	MediaStorage        string
+	MediaDir            string
+	MediaPublicURL      string
+	MediaMaxUploadSize  int
 }
 
 func LoadConfig() *Config {
@@ -28,17 +58,41 @@ func LoadConfig() *Config {
 	}
 
 	return &Config{
-		Port:            getEnv("PORT", "8080"),
-		GinMode:         getEnv("GIN_MODE", "debug"),
-		MongoDBURI:      getEnv("MONGODB_URI", "mongodb://localhost:27017"),
-		MongoDBDatabase: getEnv("MONGODB_DATABASE", "portfolio_db"),
-		JWTSecret:       getEnv("JWT_SECRET", "your-super-secret-jwt-key-here"),
-		JWTExpiry:       getEnv("JWT_EXPIRY", "24h"),
-		AllowedOrigins:  getEnv("ALLOWED_ORIGINS", "http://localhost:5173,http://localhost:3000"),
-		SMTPHost:        getEnv("SMTP_HOST", "smtp.gmail.com"),
-		SMTPPort:        getEnv("SMTP_PORT", "587"),
-		SMTPUsername:    getEnv("SMTP_USERNAME", ""),
-		SMTPPassword:    getEnv("SMTP_PASSWORD", ""),
+		Port:                getEnv("PORT", "8080"),
+		GinMode:             getEnv("GIN_MODE", "debug"),
+		MongoDBURI:          getEnv("MONGODB_URI", "mongodb://localhost:27017"),
+		MongoDBDatabase:     getEnv("MONGODB_DATABASE", "portfolio_db"),
+		StorageBackend:      getEnv("STORAGE_BACKEND", "mongodb"),
+		MongoDBSchema:       getEnv("MONGODB_SCHEMA", "apply"),
+		MongoDBMigrations:   getEnv("MONGODB_MIGRATIONS", "apply"),
+		JWTSecret:           getEnv("JWT_SECRET", DefaultJWTSecret),
+		JWTKeysDir:          getEnv("JWT_KEYS_DIR", ""),
+		JWTActiveKID:        getEnv("JWT_ACTIVE_KID", ""),
+		JWTExpiry:           getEnv("JWT_EXPIRY", "15m"),
+		RefreshExpiry:       getEnv("REFRESH_TOKEN_EXPIRY", "720h"),
+		AllowedOrigins:      getEnv("ALLOWED_ORIGINS", "http://localhost:5173,http://localhost:3000"),
+		TrustedProxies:      getEnv("TRUSTED_PROXIES", ""),
+		SMTPHost:            getEnv("SMTP_HOST", "smtp.gmail.com"),
+		SMTPPort:            getEnv("SMTP_PORT", "587"),
+		SMTPUsername:        getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:        getEnv("SMTP_PASSWORD", ""),
+		AdminUsername:       getEnv("ADMIN_USERNAME", "admin"),
+		AdminPassword:       getEnv("ADMIN_PASSWORD", ""),
+		AdminDisplayName:    getEnv("ADMIN_DISPLAY_NAME", "Administrator"),
+		AdminEmail:          getEnv("ADMIN_EMAIL", ""),
+		MFAIssuer:           getEnv("MFA_ISSUER", "Portfolio"),
+		LoginMaxAttempts:    getEnvInt("LOGIN_MAX_ATTEMPTS", 5),
+		LoginIPMaxAttempts:  getEnvInt("LOGIN_IP_MAX_ATTEMPTS", 20),
+		LoginLockout:        getEnv("LOGIN_LOCKOUT_DURATION", "15m"),
+		PasswordResetURL:    getEnv("PASSWORD_RESET_URL", "http://localhost:5173/admin/reset-password"),
+		PasswordResetExpiry: getEnv("PASSWORD_RESET_EXPIRY", "1h"),
+		TrashRetention:      getEnv("TRASH_RETENTION", "720h"),
+		TrashPurgeInterval:  getEnv("TRASH_PURGE_INTERVAL", "1h"),
+		SchedulerInterval:   getEnv("PROJECT_SCHEDULER_INTERVAL", "1m"),
+		MediaStorage:        getEnv("MEDIA_STORAGE", "local"),
+		MediaDir:            getEnv("MEDIA_DIR", "./uploads"),
+		MediaPublicURL:      getEnv("MEDIA_PUBLIC_URL", ""),
+		MediaMaxUploadSize:  getEnvInt("MEDIA_MAX_UPLOAD_SIZE", 10<<20),
 	}
 }
 
@@ -48,3 +102,20 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// ParseDuration parses value as a time.Duration, falling back to defaultValue
+// when it is malformed.
+func ParseDuration(value string, defaultValue time.Duration) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return defaultValue
+	}
+	return d
+}
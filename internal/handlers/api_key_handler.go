@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/services"
+	"portfolio-backend/internal/store"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string              `json:"name" binding:"required"`
+	Scopes    []models.Permission `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time          `json:"expires_at"`
+}
+
+type APIKeyHandler struct {
+	apiKeyService *services.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey issues a new API key; the plain key is only returned here
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+
+	key, plain, err := h.apiKeyService.CreateAPIKey(req.Name, req.Scopes, req.ExpiresAt, c.GetString("username"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidScope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing scopes", "valid_scopes": models.AllPermissions})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "API key created; store it now, it will not be shown again",
+		"key":     plain,
+		"api_key": key,
+	})
+}
+
+// GetAllAPIKeys lists API keys without their secrets
+func (h *APIKeyHandler) GetAllAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.GetAllAPIKeys()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch API keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys": keys,
+	})
+}
+
+// RevokeAPIKey revokes an API key immediately
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.apiKeyService.RevokeAPIKey(id); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API key revoked successfully",
+	})
+}
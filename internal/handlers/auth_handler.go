@@ -1,14 +1,17 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/crypto/bcrypt"
 
-	"portfolio-backend/configs"
-	"portfolio-backend/internal/middleware"
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/services"
+	"portfolio-backend/internal/store"
 )
 
 type LoginRequest struct {
@@ -16,13 +19,40 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
 type AuthHandler struct {
-	config *configs.Config
+	userService  *services.UserService
+	tokenService *services.TokenService
+	mfaService   *services.MFAService
+	loginGuard   *services.LoginGuard
+	loginAudit   *services.LoginAuditService
 }
 
-func NewAuthHandler(config *configs.Config) *AuthHandler {
+func NewAuthHandler(
+	userService *services.UserService,
+	tokenService *services.TokenService,
+	mfaService *services.MFAService,
+	loginGuard *services.LoginGuard,
+	loginAudit *services.LoginAuditService,
+) *AuthHandler {
 	return &AuthHandler{
-		config: config,
+		userService:  userService,
+		tokenService: tokenService,
+		mfaService:   mfaService,
+		loginGuard:   loginGuard,
+		loginAudit:   loginAudit,
 	}
 }
 
@@ -33,39 +63,238 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// For demo purposes, using hardcoded admin credentials
-	// In production, you should store these in the database with proper hashing
-	adminUsername := "admin"
-	adminPasswordHash := "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi" // "password"
+	if !h.checkLoginAllowed(c, req.Username) {
+		return
+	}
 
-	if req.Username != adminUsername {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+	user, err := h.userService.Authenticate(req.Username, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCredentials):
+			h.recordFailure(c, req.Username, models.LoginReasonInvalidCredentials)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		case errors.Is(err, services.ErrUserDisabled):
+			h.recordFailure(c, req.Username, models.LoginReasonDisabled)
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate"})
+		}
 		return
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(adminPasswordHash), []byte(req.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+	// Users with two-factor authentication get a challenge token instead
+	if user.TOTPEnabled {
+		mfaToken, expiresIn, err := h.tokenService.IssueMFAChallenge(user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+			return
+		}
+
+		h.loginAudit.Record(user.Username, c.ClientIP(), c.Request.UserAgent(), false, models.LoginReasonMFARequired)
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "Two-factor authentication required",
+			"mfa_required": true,
+			"mfa_token":    mfaToken,
+			"expires_in":   expiresIn,
+		})
 		return
 	}
 
-	// Parse JWT expiry duration
-	expiry, err := time.ParseDuration(h.config.JWTExpiry)
+	h.completeLogin(c, user)
+}
+
+// LoginMFA completes a two-factor login with a TOTP or recovery code
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	var req MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	username, err := h.tokenService.ParseMFAChallenge(req.MFAToken)
 	if err != nil {
-		expiry = 24 * time.Hour // Default to 24 hours
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired MFA token"})
+		return
+	}
+
+	if !h.checkLoginAllowed(c, username) {
+		return
 	}
 
-	// Generate JWT token
-	token, err := middleware.GenerateToken(req.Username, h.config.JWTSecret, expiry)
+	user, err := h.userService.GetUserByUsername(username)
+	if err != nil || user.Disabled {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired MFA token"})
+		return
+	}
+
+	if err := h.mfaService.Verify(user, req.Code); err != nil {
+		if errors.Is(err, services.ErrInvalidMFACode) {
+			h.recordFailure(c, username, models.LoginReasonInvalidMFACode)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid two-factor code"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify two-factor code"})
+		return
+	}
+
+	h.completeLogin(c, user)
+}
+
+func (h *AuthHandler) completeLogin(c *gin.Context, user *models.User) {
+	if err := h.userService.RecordLogin(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate"})
+		return
+	}
+
+	tokens, err := h.tokenService.IssueTokens(user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
+	h.loginGuard.RecordSuccess(user.Username)
+	h.loginAudit.Record(user.Username, c.ClientIP(), c.Request.UserAgent(), true, models.LoginReasonSuccess)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Login successful",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"token_type":    tokens.TokenType,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user.ToResponse(),
+	})
+}
+
+// checkLoginAllowed rejects the request with 429 while the username or client
+// IP is throttled or locked out.
+func (h *AuthHandler) checkLoginAllowed(c *gin.Context, username string) bool {
+	wait, ok := h.loginGuard.Check(username, c.ClientIP())
+	if ok {
+		return true
+	}
+
+	h.loginAudit.Record(username, c.ClientIP(), c.Request.UserAgent(), false, models.LoginReasonLocked)
+	seconds := int(math.Ceil(wait.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       "Too many failed login attempts. Please try again later.",
+		"retry_after": seconds,
+	})
+	return false
+}
+
+func (h *AuthHandler) recordFailure(c *gin.Context, username, reason string) {
+	h.loginGuard.RecordFailure(username, c.ClientIP())
+	h.loginAudit.Record(username, c.ClientIP(), c.Request.UserAgent(), false, reason)
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, user, err := h.tokenService.Refresh(req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRefreshToken):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		case errors.Is(err, services.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token already used; session revoked"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"token_type":    tokens.TokenType,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user.ToResponse(),
+	})
+}
+
+// Logout revokes the current access token and, if given, the refresh token family
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	expiresAt, _ := c.Get("token_expires_at")
+	expiry, ok := expiresAt.(time.Time)
+	if !ok {
+		expiry = time.Now()
+	}
+
+	if err := h.tokenService.RevokeAccessToken(c.GetString("jti"), expiry); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	if req.RefreshToken != "" {
+		if err := h.tokenService.RevokeRefreshToken(req.RefreshToken); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out successfully",
+	})
+}
+
+// GetLoginEvents returns the login audit trail (admin only)
+func (h *AuthHandler) GetLoginEvents(c *gin.Context) {
+	filter := store.LoginEventFilter{
+		Username: c.Query("username"),
+		IP:       c.Query("ip"),
+		Limit:    100,
+	}
+
+	if value := c.Query("success"); value != "" {
+		success, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "success must be true or false"})
+			return
+		}
+		filter.Success = &success
+	}
+
+	for param, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be an RFC 3339 timestamp"})
+				return
+			}
+			*target = t
+		}
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || limit < 1 || limit > 1000 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	events, err := h.loginAudit.GetLoginEvents(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch login events"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"token":   token,
-		"user": gin.H{
-			"username": req.Username,
-		},
+		"events": events,
 	})
 }
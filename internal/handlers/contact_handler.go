@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"portfolio-backend/internal/models"
 	"portfolio-backend/internal/services"
+	"portfolio-backend/internal/store"
 )
 
 type ContactHandler struct {
@@ -38,16 +40,52 @@ func (h *ContactHandler) CreateContact(c *gin.Context) {
 	})
 }
 
-// GetAllContacts retrieves all contact messages (admin only)
+// GetAllContacts retrieves contact messages with pagination, filtering and
+// sorting (admin only)
 func (h *ContactHandler) GetAllContacts(c *gin.Context) {
-	contacts, err := h.contactService.GetAllContacts()
+	var params services.ContactListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contacts, pagination, err := h.contactService.GetContacts(params)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidListParams) || errors.Is(err, store.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch contacts"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"contacts": contacts,
+		"contacts":   contacts,
+		"pagination": pagination,
+	})
+}
+
+// SearchContacts searches contacts by name, email, subject and message (admin only)
+func (h *ContactHandler) SearchContacts(c *gin.Context) {
+	var params services.SearchParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := h.contactService.SearchContacts(params)
+	if err != nil {
+		if errors.Is(err, services.ErrEmptySearchQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search contacts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":   params.Query,
+		"results": results,
 	})
 }
 
@@ -60,7 +98,7 @@ func (h *ContactHandler) GetContactByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respondWithETag(c, versionETag(contact.Version), gin.H{
 		"contact": contact,
 	})
 }
@@ -68,25 +106,111 @@ func (h *ContactHandler) GetContactByID(c *gin.Context) {
 // MarkAsRead marks a contact message as read
 func (h *ContactHandler) MarkAsRead(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.contactService.MarkAsRead(id); err != nil {
+	contact, err := h.contactService.MarkAsRead(id, ifMatchVersions(c))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+			return
+		}
+		if errors.Is(err, store.ErrVersionConflict) {
+			respondPreconditionFailed(c, "Contact")
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark contact as read"})
 		return
 	}
 
+	c.Header("ETag", versionETag(contact.Version))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Contact marked as read",
 	})
 }
 
-// DeleteContact deletes a contact message
+// DeleteContact moves a contact message to the trash
 func (h *ContactHandler) DeleteContact(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.contactService.DeleteContact(id); err != nil {
+	if err := h.contactService.DeleteContact(id, ifMatchVersions(c)); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+			return
+		}
+		if errors.Is(err, store.ErrVersionConflict) {
+			respondPreconditionFailed(c, "Contact")
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete contact"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Contact deleted successfully",
+		"message": "Contact moved to trash",
+	})
+}
+
+// GetTrashedContacts lists the contact trash with the same parameters as
+// GetAllContacts
+func (h *ContactHandler) GetTrashedContacts(c *gin.Context) {
+	var params services.ContactListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contacts, pagination, err := h.contactService.GetTrashedContacts(params)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidListParams) || errors.Is(err, store.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"contacts":   contacts,
+		"pagination": pagination,
+	})
+}
+
+// RestoreContact takes a contact message out of the trash
+func (h *ContactHandler) RestoreContact(c *gin.Context) {
+	contact, err := h.contactService.RestoreContact(c.Param("id"), ifMatchVersions(c))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found in trash"})
+			return
+		}
+		if errors.Is(err, store.ErrVersionConflict) {
+			respondPreconditionFailed(c, "Contact")
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore contact"})
+		return
+	}
+
+	c.Header("ETag", versionETag(contact.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Contact restored successfully",
+		"contact": contact,
+	})
+}
+
+// PurgeContact permanently deletes a contact message from the trash
+func (h *ContactHandler) PurgeContact(c *gin.Context) {
+	if err := h.contactService.PurgeContact(c.Param("id"), ifMatchVersions(c)); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found in trash"})
+			return
+		}
+		if errors.Is(err, store.ErrVersionConflict) {
+			respondPreconditionFailed(c, "Contact")
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge contact"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Contact permanently deleted",
 	})
 }
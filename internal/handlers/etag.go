@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"portfolio-backend/internal/models"
+)
+
+// versionETag is the strong ETag of a resource at version.
+func versionETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// formatETag is versionETag for a response limited to one content format,
+// such as "3-html", since it is a different representation than the full
+// response. An empty format means the full response.
+func formatETag(version int64, format string) string {
+	if format == "" {
+		return versionETag(version)
+	}
+	return `"` + strconv.FormatInt(version, 10) + "-" + format + `"`
+}
+
+// bodyETag is a weak ETag for a response without a single version, such as a
+// list, derived from its JSON encoding.
+func bodyETag(body interface{}) string {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return `W/"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// ifMatchVersions parses the If-Match header into resource versions, taking
+// the ETags of every format of a version. It returns nil when there is no
+// header or it is "*", meaning any version: the check is opt-in, and writes
+// without If-Match always apply. Weak and unrecognised ETags never match, so
+// they yield an empty, non-nil list.
+func ifMatchVersions(c *gin.Context) []int64 {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	versions := []int64{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) || len(tag) < 2 {
+			continue
+		}
+		value := tag[1 : len(tag)-1]
+		if version, format, ok := strings.Cut(value, "-"); ok && slices.Contains(models.Formats, format) {
+			value = version
+		}
+		if version, err := strconv.ParseInt(value, 10, 64); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// respondWithETag sets the ETag header and writes body, or a bare 304 when
+// the request's If-None-Match already matches etag.
+func respondWithETag(c *gin.Context, etag string, body interface{}) {
+	if etag != "" {
+		c.Header("ETag", etag)
+		if ifNoneMatch(c.GetHeader("If-None-Match"), etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// ifNoneMatch reports whether header matches etag using weak comparison.
+func ifNoneMatch(header, etag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// respondPreconditionFailed writes the response for a failed If-Match.
+func respondPreconditionFailed(c *gin.Context, resource string) {
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error": resource + " has been modified; fetch the latest version and retry",
+	})
+}
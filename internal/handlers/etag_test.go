@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFormatETag(t *testing.T) {
+	tests := []struct {
+		format, want string
+	}{
+		{"", `"3"`},
+		{"markdown", `"3-markdown"`},
+		{"html", `"3-html"`},
+	}
+	for _, tt := range tests {
+		if got := formatETag(3, tt.format); got != tt.want {
+			t.Errorf("formatETag(3, %q) = %s, want %s", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestIfMatchVersions(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []int64
+	}{
+		{"", nil},
+		{"*", nil},
+		{`"3"`, []int64{3}},
+		{`"3-html", "4-markdown"`, []int64{3, 4}},
+		{`"3", W/"4", "5-pdf", "x"`, []int64{3}},
+		{`W/"3"`, []int64{}},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("PUT", "/", nil)
+		if tt.header != "" {
+			c.Request.Header.Set("If-Match", tt.header)
+		}
+		if got := ifMatchVersions(c); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ifMatchVersions(%s) = %#v, want %#v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/services"
+	"portfolio-backend/internal/store"
+)
+
+// multipartOverhead allows for the multipart framing and form fields around
+// an upload of the maximum size.
+const multipartOverhead = 1 << 20
+
+type MediaHandler struct {
+	mediaService *services.MediaService
+}
+
+func NewMediaHandler(mediaService *services.MediaService) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+	}
+}
+
+// UploadMedia stores an image sent as the multipart field "file", with an
+// optional "alt" text
+func (h *MediaHandler) UploadMedia(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.mediaService.MaxSize()+multipartOverhead)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.respondTooLarge(c)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A file must be sent as the multipart field \"file\""})
+		return
+	}
+	defer file.Close()
+
+	media, err := h.mediaService.Upload(file, header.Filename, c.Request.FormValue("alt"), c.GetString("username"))
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrMediaTooLarge):
+			h.respondTooLarge(c)
+		case errors.Is(err, services.ErrUnsupportedMedia):
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Only JPEG, PNG, GIF and WebP images are accepted"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload media"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Media uploaded successfully",
+		"media":   media,
+	})
+}
+
+// GetMedia lists the media library with pagination
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	var params services.MediaListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	media, pagination, err := h.mediaService.GetMedia(params)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidListParams) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"media":      media,
+		"pagination": pagination,
+	})
+}
+
+// GetMediaByID retrieves the metadata of an upload
+func (h *MediaHandler) GetMediaByID(c *gin.Context) {
+	media, err := h.mediaService.GetMediaByID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch media")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"media": media,
+	})
+}
+
+// GetMediaFile serves an uploaded file
+func (h *MediaHandler) GetMediaFile(c *gin.Context) {
+	h.serveFile(c, models.MediaVariantOriginal)
+}
+
+// GetMediaVariant serves a resized variant of an uploaded image
+func (h *MediaHandler) GetMediaVariant(c *gin.Context) {
+	h.serveFile(c, c.Param("name"))
+}
+
+// serveFile streams a stored file. Files never change once stored, so they
+// may be cached indefinitely.
+func (h *MediaHandler) serveFile(c *gin.Context, variant string) {
+	media, stored, file, err := h.mediaService.OpenMedia(c.Param("id"), variant)
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch media")
+		return
+	}
+	defer file.Close()
+
+	etag := `"` + stored.SHA256 + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	filename := media.Filename
+	if variant != models.MediaVariantOriginal {
+		filename = path.Base(stored.Key)
+	}
+	c.DataFromReader(http.StatusOK, stored.Size, stored.ContentType, file, map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"Content-Disposition":    mime.FormatMediaType("inline", map[string]string{"filename": filename}),
+	})
+}
+
+// DeleteMedia deletes an upload that no project or post uses
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	if err := h.mediaService.DeleteMedia(c.Param("id")); err != nil {
+		if errors.Is(err, services.ErrMediaInUse) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Media is used by a project or post; remove it from the project's gallery or the post's cover first"})
+			return
+		}
+		h.respondError(c, err, "Failed to delete media")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Media deleted successfully",
+	})
+}
+
+func (h *MediaHandler) respondTooLarge(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error": fmt.Sprintf("Images must be at most %d bytes", h.mediaService.MaxSize()),
+	})
+}
+
+func (h *MediaHandler) respondError(c *gin.Context, err error, message string) {
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"portfolio-backend/internal/services"
+)
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFAHandler struct {
+	mfaService *services.MFAService
+}
+
+func NewMFAHandler(mfaService *services.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+// EnrollTOTP starts TOTP enrollment for the authenticated user
+func (h *MFAHandler) EnrollTOTP(c *gin.Context) {
+	enrollment, err := h.mfaService.BeginEnrollment(c.GetString("username"))
+	if err != nil {
+		if errors.Is(err, services.ErrMFAAlreadyEnabled) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start enrollment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Scan the provisioning URI and confirm with a code",
+		"enrollment": enrollment,
+	})
+}
+
+// ConfirmTOTP activates TOTP after verifying a code from the authenticator app
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.mfaService.ConfirmEnrollment(c.GetString("username"), req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidMFACode):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid two-factor code"})
+		case errors.Is(err, services.ErrMFAEnrollmentMissing):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No pending enrollment"})
+		case errors.Is(err, services.ErrMFAAlreadyEnabled):
+			c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to confirm enrollment"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTOTP turns TOTP off after verifying a code or recovery code
+func (h *MFAHandler) DisableTOTP(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.mfaService.Disable(c.GetString("username"), req.Code); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidMFACode):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid two-factor code"})
+		case errors.Is(err, services.ErrMFANotEnabled):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable two-factor authentication"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Two-factor authentication disabled",
+	})
+}
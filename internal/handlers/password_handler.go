@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"portfolio-backend/internal/services"
+)
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+type PasswordHandler struct {
+	userService  *services.UserService
+	tokenService *services.TokenService
+	resetService *services.PasswordResetService
+}
+
+func NewPasswordHandler(userService *services.UserService, tokenService *services.TokenService, resetService *services.PasswordResetService) *PasswordHandler {
+	return &PasswordHandler{
+		userService:  userService,
+		tokenService: tokenService,
+		resetService: resetService,
+	}
+}
+
+// ChangePassword changes the authenticated user's password. Other sessions
+// are signed out and a fresh token pair is returned for the caller.
+func (h *PasswordHandler) ChangePassword(c *gin.Context) {
+	if c.GetString("auth_method") != "jwt" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Password changes require a user login"})
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.userService.ChangePassword(c.GetString("username"), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		var weak *services.PasswordStrengthError
+		switch {
+		case errors.Is(err, services.ErrInvalidCredentials):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+		case errors.As(err, &weak):
+			c.JSON(http.StatusBadRequest, gin.H{"error": weak.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change password"})
+		}
+		return
+	}
+
+	if err := h.tokenService.RevokeUserSessions(user.Username); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	tokens, err := h.tokenService.IssueTokens(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Password changed successfully",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"token_type":    tokens.TokenType,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+// ForgotPassword emails a reset link. The response is the same whether or
+// not the address belongs to a user.
+func (h *PasswordHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.resetService.RequestReset(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request password reset"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "If the address belongs to an account, a reset link has been sent",
+	})
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword
+func (h *PasswordHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.resetService.ResetPassword(req.Token, req.NewPassword); err != nil {
+		var weak *services.PasswordStrengthError
+		switch {
+		case errors.Is(err, services.ErrInvalidResetToken):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired reset token"})
+		case errors.As(err, &weak):
+			c.JSON(http.StatusBadRequest, gin.H{"error": weak.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password reset successfully; please log in again",
+	})
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/services"
+	"portfolio-backend/internal/store"
+)
+
+type PostHandler struct {
+	postService *services.PostService
+}
+
+func NewPostHandler(postService *services.PostService) *PostHandler {
+	return &PostHandler{
+		postService: postService,
+	}
+}
+
+// CreatePost creates a new blog post
+func (h *PostHandler) CreatePost(c *gin.Context) {
+	var post models.Post
+	if err := c.ShouldBindJSON(&post); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.postService.CreatePost(&post, c.GetString("username")); err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
+		return
+	}
+
+	c.Header("ETag", versionETag(post.Version))
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Post created successfully",
+		"post":    post,
+	})
+}
+
+// GetAllPosts retrieves the published posts, newest first, with pagination
+func (h *PostHandler) GetAllPosts(c *gin.Context) {
+	h.listPosts(c, false)
+}
+
+// GetAllPostsAdmin retrieves every post, drafts included (admin only)
+func (h *PostHandler) GetAllPostsAdmin(c *gin.Context) {
+	h.listPosts(c, true)
+}
+
+// GetPostsByTag retrieves the published posts with a tag
+func (h *PostHandler) GetPostsByTag(c *gin.Context) {
+	h.listPosts(c, false)
+}
+
+func (h *PostHandler) listPosts(c *gin.Context, includeUnpublished bool) {
+	var params services.PostListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if tag := c.Param("tag"); tag != "" {
+		params.Tag = tag
+	}
+	format, ok := contentFormat(c)
+	if !ok {
+		return
+	}
+
+	posts, pagination, err := h.postService.GetPosts(params, includeUnpublished)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidListParams) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	for i := range posts {
+		posts[i].SelectFormat(format)
+	}
+	body := gin.H{
+		"posts":      posts,
+		"pagination": pagination,
+	}
+	respondWithETag(c, bodyETag(body), body)
+}
+
+// GetPostTags lists the tags of published posts with their post counts
+func (h *PostHandler) GetPostTags(c *gin.Context) {
+	tags, err := h.postService.GetPostTags(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+
+	body := gin.H{
+		"tags": tags,
+	}
+	respondWithETag(c, bodyETag(body), body)
+}
+
+// GetPostByID retrieves a published post
+func (h *PostHandler) GetPostByID(c *gin.Context) {
+	h.getPost(c, false)
+}
+
+// GetPostByIDAdmin retrieves a post, drafts included (admin only)
+func (h *PostHandler) GetPostByIDAdmin(c *gin.Context) {
+	h.getPost(c, true)
+}
+
+func (h *PostHandler) getPost(c *gin.Context, includeUnpublished bool) {
+	format, ok := contentFormat(c)
+	if !ok {
+		return
+	}
+	post, err := h.postService.GetPostByID(c.Param("id"), includeUnpublished)
+	h.respondPost(c, post, err, format)
+}
+
+// GetPostBySlug retrieves a published post by slug
+func (h *PostHandler) GetPostBySlug(c *gin.Context) {
+	format, ok := contentFormat(c)
+	if !ok {
+		return
+	}
+	post, err := h.postService.GetPostBySlug(c.Param("slug"))
+	h.respondPost(c, post, err, format)
+}
+
+func (h *PostHandler) respondPost(c *gin.Context, post *models.PostResponse, err error, format string) {
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	post.SelectFormat(format)
+	respondWithETag(c, formatETag(post.Version, format), gin.H{
+		"post": post,
+	})
+}
+
+// UpdatePost updates a blog post
+func (h *PostHandler) UpdatePost(c *gin.Context) {
+	var post models.Post
+	if err := c.ShouldBindJSON(&post); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.postService.UpdatePost(c.Param("id"), &post, ifMatchVersions(c)); err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		if errors.Is(err, store.ErrVersionConflict) {
+			respondPreconditionFailed(c, "Post")
+			return
+		}
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+		return
+	}
+
+	c.Header("ETag", versionETag(post.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post updated successfully",
+		"post":    post,
+	})
+}
+
+// DeletePost permanently deletes a blog post
+func (h *PostHandler) DeletePost(c *gin.Context) {
+	if err := h.postService.DeletePost(c.Param("id"), ifMatchVersions(c)); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			return
+		}
+		if errors.Is(err, store.ErrVersionConflict) {
+			respondPreconditionFailed(c, "Post")
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete post"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post deleted successfully",
+	})
+}
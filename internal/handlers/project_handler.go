@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"portfolio-backend/internal/jsonpatch"
 	"portfolio-backend/internal/models"
 	"portfolio-backend/internal/services"
+	"portfolio-backend/internal/store"
 )
 
 type ProjectHandler struct {
@@ -27,53 +32,176 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 		return
 	}
 
-	if err := h.projectService.CreateProject(&project); err != nil {
+	if err := h.projectService.CreateProject(&project, c.GetString("username")); err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project"})
 		return
 	}
 
+	c.Header("ETag", versionETag(project.Version))
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Project created successfully",
 		"project": project,
 	})
 }
 
-// GetAllProjects retrieves all projects
+// GetAllProjects retrieves the currently published projects with
+// pagination, filters and facets
 func (h *ProjectHandler) GetAllProjects(c *gin.Context) {
-	projects, err := h.projectService.GetAllProjects()
+	h.listProjects(c, false)
+}
+
+// GetAllProjectsAdmin retrieves projects in every status, optionally
+// filtered by status (admin only)
+func (h *ProjectHandler) GetAllProjectsAdmin(c *gin.Context) {
+	h.listProjects(c, true)
+}
+
+func (h *ProjectHandler) listProjects(c *gin.Context, includeUnpublished bool) {
+	var params services.ProjectListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format, ok := contentFormat(c)
+	if !ok {
+		return
+	}
+
+	projects, pagination, err := h.projectService.GetProjects(params, includeUnpublished)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidListParams) || errors.Is(err, store.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"projects": projects,
-	})
+	facets, err := h.projectService.GetProjectFacets(includeUnpublished)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
+		return
+	}
+
+	selectFormat(projects, format)
+	body := gin.H{
+		"projects":   projects,
+		"pagination": pagination,
+		"facets":     facets,
+	}
+	respondWithETag(c, bodyETag(body), body)
 }
 
 // GetFeaturedProjects retrieves featured projects
 func (h *ProjectHandler) GetFeaturedProjects(c *gin.Context) {
+	format, ok := contentFormat(c)
+	if !ok {
+		return
+	}
 	projects, err := h.projectService.GetFeaturedProjects()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch featured projects"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	selectFormat(projects, format)
+	body := gin.H{
 		"projects": projects,
-	})
+	}
+	respondWithETag(c, bodyETag(body), body)
 }
 
-// GetProjectByID retrieves a specific project
+// SearchProjects searches projects by title, description, technologies and category
+func (h *ProjectHandler) SearchProjects(c *gin.Context) {
+	var params services.SearchParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format, ok := contentFormat(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.projectService.SearchProjects(params)
+	if err != nil {
+		if errors.Is(err, services.ErrEmptySearchQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search projects"})
+		return
+	}
+
+	for i := range results {
+		results[i].Project.SelectFormat(format)
+	}
+	body := gin.H{
+		"query":   params.Query,
+		"results": results,
+	}
+	respondWithETag(c, bodyETag(body), body)
+}
+
+// GetProjectByID retrieves a specific published project
 func (h *ProjectHandler) GetProjectByID(c *gin.Context) {
+	h.getProject(c, false)
+}
+
+// GetProjectByIDAdmin retrieves a specific project in any status (admin only)
+func (h *ProjectHandler) GetProjectByIDAdmin(c *gin.Context) {
+	h.getProject(c, true)
+}
+
+func (h *ProjectHandler) getProject(c *gin.Context, includeUnpublished bool) {
+	format, ok := contentFormat(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
-	project, err := h.projectService.GetProjectByID(id)
+	project, err := h.projectService.GetProjectByID(id, includeUnpublished)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		return
 	}
+	project.SelectFormat(format)
 
-	c.JSON(http.StatusOK, gin.H{
+	respondWithETag(c, formatETag(project.Version, format), gin.H{
+		"project": project,
+	})
+}
+
+// GetProjectBySlug retrieves a published project by slug, redirecting
+// permanently from slugs the project had before it was renamed
+func (h *ProjectHandler) GetProjectBySlug(c *gin.Context) {
+	format, ok := contentFormat(c)
+	if !ok {
+		return
+	}
+	value := c.Param("slug")
+	project, redirect, err := h.projectService.GetProjectBySlug(value)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project"})
+		return
+	}
+	if redirect != "" {
+		location := strings.TrimSuffix(c.Request.URL.Path, value) + redirect
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+	project.SelectFormat(format)
+
+	respondWithETag(c, formatETag(project.Version, format), gin.H{
 		"project": project,
 	})
 }
@@ -87,26 +215,233 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 		return
 	}
 
-	if err := h.projectService.UpdateProject(id, &project); err != nil {
+	if err := h.projectService.UpdateProject(id, &project, ifMatchVersions(c), c.GetString("username")); err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		if errors.Is(err, store.ErrVersionConflict) {
+			respondPreconditionFailed(c, "Project")
+			return
+		}
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
 		return
 	}
 
+	c.Header("ETag", versionETag(project.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project updated successfully",
+		"project": project,
+	})
+}
+
+// PatchProject partially updates a project with a JSON Merge Patch or, for
+// Content-Type application/json-patch+json, a JSON Patch
+func (h *ProjectHandler) PatchProject(c *gin.Context) {
+	var kind services.PatchKind
+	switch c.ContentType() {
+	case "application/merge-patch+json", "application/json":
+		kind = services.MergePatch
+	case "application/json-patch+json":
+		kind = services.JSONPatch
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "Content-Type must be application/merge-patch+json or application/json-patch+json",
+		})
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.projectService.PatchProject(c.Param("id"), kind, patch, ifMatchVersions(c), c.GetString("username"))
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		case errors.Is(err, store.ErrVersionConflict):
+			respondPreconditionFailed(c, "Project")
+		case errors.Is(err, jsonpatch.ErrTestFailed):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, jsonpatch.ErrInvalidPatch), errors.Is(err, jsonpatch.ErrPathNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
+		}
+		return
+	}
+
+	c.Header("ETag", versionETag(project.Version))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Project updated successfully",
 		"project": project,
 	})
 }
 
-// DeleteProject deletes a project
+// ReorderProjectsRequest lists project ids in their new order.
+type ReorderProjectsRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
+// ReorderProjects sets the manual order of the listed projects
+func (h *ProjectHandler) ReorderProjects(c *gin.Context) {
+	var req ReorderProjectsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	projects, err := h.projectService.ReorderProjects(req.IDs, c.GetString("username"))
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		if errors.Is(err, store.ErrVersionConflict) || errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Projects changed while reordering; try again"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder projects"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Projects reordered successfully",
+		"projects": projects,
+	})
+}
+
+// DeleteProject moves a project to the trash
 func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.projectService.DeleteProject(id); err != nil {
+	if err := h.projectService.DeleteProject(id, ifMatchVersions(c), c.GetString("username")); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			return
+		}
+		if errors.Is(err, store.ErrVersionConflict) {
+			respondPreconditionFailed(c, "Project")
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Project deleted successfully",
+		"message": "Project moved to trash",
+	})
+}
+
+// GetTrashedProjects lists the project trash with the same parameters as
+// GetAllProjects
+func (h *ProjectHandler) GetTrashedProjects(c *gin.Context) {
+	var params services.ProjectListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format, ok := contentFormat(c)
+	if !ok {
+		return
+	}
+
+	projects, pagination, err := h.projectService.GetTrashedProjects(params)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidListParams) || errors.Is(err, store.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+		return
+	}
+
+	selectFormat(projects, format)
+	c.JSON(http.StatusOK, gin.H{
+		"projects":   projects,
+		"pagination": pagination,
+	})
+}
+
+// RestoreProject takes a project out of the trash
+func (h *ProjectHandler) RestoreProject(c *gin.Context) {
+	project, err := h.projectService.RestoreProject(c.Param("id"), ifMatchVersions(c), c.GetString("username"))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found in trash"})
+			return
+		}
+		if errors.Is(err, store.ErrVersionConflict) {
+			respondPreconditionFailed(c, "Project")
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore project"})
+		return
+	}
+
+	c.Header("ETag", versionETag(project.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project restored successfully",
+		"project": project,
+	})
+}
+
+// PurgeProject permanently deletes a project from the trash
+func (h *ProjectHandler) PurgeProject(c *gin.Context) {
+	if err := h.projectService.PurgeProject(c.Param("id"), ifMatchVersions(c)); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found in trash"})
+			return
+		}
+		if errors.Is(err, store.ErrVersionConflict) {
+			respondPreconditionFailed(c, "Project")
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge project"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project permanently deleted",
+	})
+}
+
+// contentFormat returns the representation of Markdown content chosen with
+// the format query parameter, empty for all of them. It writes a 400
+// response for an unknown format and reports whether the format was valid.
+func contentFormat(c *gin.Context) (string, bool) {
+	format := c.Query("format")
+	if format != "" && !slices.Contains(models.Formats, format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of " + strings.Join(models.Formats, ", ")})
+		return "", false
+	}
+	return format, true
+}
+
+// selectFormat applies format to every project.
+func selectFormat(projects []models.ProjectResponse, format string) {
+	for i := range projects {
+		projects[i].SelectFormat(format)
+	}
+}
+
+// respondValidationError writes a 422 response listing the invalid fields if
+// err is a validation error, and reports whether it did.
+func respondValidationError(c *gin.Context, err error) bool {
+	var validationErr *services.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error":  "Validation failed",
+		"fields": validationErr.Fields,
 	})
+	return true
 }
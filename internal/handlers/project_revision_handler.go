@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"portfolio-backend/internal/services"
+	"portfolio-backend/internal/store"
+)
+
+// GetProjectRevisions lists the revisions of a project, newest first
+func (h *ProjectHandler) GetProjectRevisions(c *gin.Context) {
+	revisions, err := h.projectService.GetRevisions(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": revisions,
+	})
+}
+
+// GetProjectRevision retrieves a single revision of a project
+func (h *ProjectHandler) GetProjectRevision(c *gin.Context) {
+	number, ok := revisionNumber(c, c.Param("number"))
+	if !ok {
+		return
+	}
+
+	revision, err := h.projectService.GetRevision(c.Param("id"), number)
+	if err != nil {
+		respondRevisionError(c, err, "Failed to fetch revision")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revision": revision,
+	})
+}
+
+// DiffProjectRevisions shows the field-level changes between two revisions
+// given by the from and to query parameters
+func (h *ProjectHandler) DiffProjectRevisions(c *gin.Context) {
+	from, ok := revisionNumber(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := revisionNumber(c, c.Query("to"))
+	if !ok {
+		return
+	}
+
+	diff, err := h.projectService.DiffRevisions(c.Param("id"), from, to)
+	if err != nil {
+		respondRevisionError(c, err, "Failed to diff revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"diff": diff,
+	})
+}
+
+// RestoreProjectRevision restores a prior revision of a project, recording
+// the result as a new revision
+func (h *ProjectHandler) RestoreProjectRevision(c *gin.Context) {
+	number, ok := revisionNumber(c, c.Param("number"))
+	if !ok {
+		return
+	}
+
+	project, err := h.projectService.RestoreRevision(c.Param("id"), number, ifMatchVersions(c), c.GetString("username"))
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		if errors.Is(err, store.ErrVersionConflict) {
+			respondPreconditionFailed(c, "Project")
+			return
+		}
+		if errors.Is(err, services.ErrTrashed) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Project is in the trash; restore it first"})
+			return
+		}
+		respondRevisionError(c, err, "Failed to restore revision")
+		return
+	}
+
+	c.Header("ETag", versionETag(project.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Revision restored successfully",
+		"project": project,
+	})
+}
+
+// revisionNumber parses a revision number, writing a 400 response if it is
+// not a positive integer.
+func revisionNumber(c *gin.Context, value string) (int64, bool) {
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Revision numbers must be positive integers"})
+		return 0, false
+	}
+	return number, true
+}
+
+func respondRevisionError(c *gin.Context, err error, message string) {
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
@@ -0,0 +1,43 @@
+// Package imaging resizes and re-encodes uploaded images and computes the
+// placeholders shown while they load.
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// JPEGQuality is the quality resized opaque images are encoded with.
+const JPEGQuality = 82
+
+// Resize scales img to width pixels wide, keeping its aspect ratio.
+func Resize(img image.Image, width int) *image.RGBA {
+	bounds := img.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// Encode encodes img as JPEG, or as PNG when it has transparent pixels, and
+// returns the data with its content type and file extension.
+func Encode(img *image.RGBA) (data []byte, contentType, ext string, err error) {
+	var buf bytes.Buffer
+	if img.Opaque() {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: JPEGQuality})
+		return buf.Bytes(), "image/jpeg", ".jpg", err
+	}
+
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	err = encoder.Encode(&buf, img)
+	return buf.Bytes(), "image/png", ".png", err
+}
@@ -0,0 +1,145 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+// placeholderWidth is the width images are scaled down to before computing
+// placeholders; more detail would not change the result noticeably.
+const placeholderWidth = 32
+
+// Placeholder returns the BlurHash (https://blurha.sh) of img with 4x3
+// components and its average color as #rrggbb.
+func Placeholder(img image.Image) (blurhash, averageColor string) {
+	small := img
+	if img.Bounds().Dx() > placeholderWidth {
+		small = Resize(img, placeholderWidth)
+	}
+	return Blurhash(small, 4, 3), AverageColor(small)
+}
+
+// AverageColor returns the mean color of img as #rrggbb, averaged in linear
+// light. Transparent pixels count in proportion to their opacity.
+func AverageColor(img image.Image) string {
+	bounds := img.Bounds()
+	var r, g, b, weight float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			alpha := float64(c.A) / 255
+			r += srgbToLinear(c.R) * alpha
+			g += srgbToLinear(c.G) * alpha
+			b += srgbToLinear(c.B) * alpha
+			weight += alpha
+		}
+	}
+	if weight == 0 {
+		return "#000000"
+	}
+	return fmt.Sprintf("#%02x%02x%02x", linearToSRGB(r/weight), linearToSRGB(g/weight), linearToSRGB(b/weight))
+}
+
+// Blurhash encodes img as a BlurHash with the given number of horizontal
+// and vertical components, each between 1 and 9.
+func Blurhash(img image.Image, xComponents, yComponents int) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// Convert once; every component visits every pixel.
+	pixels := make([][3]float64, 0, width*height)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			pixels = append(pixels, [3]float64{srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B)})
+		}
+	}
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1
+			}
+			var factor [3]float64
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					basis := normalisation *
+						math.Cos(math.Pi*float64(i)*float64(x)/float64(width)) *
+						math.Cos(math.Pi*float64(j)*float64(y)/float64(height))
+					pixel := pixels[y*width+x]
+					factor[0] += basis * pixel[0]
+					factor[1] += basis * pixel[1]
+					factor[2] += basis * pixel[2]
+				}
+			}
+			scale := 1 / float64(width*height)
+			factors = append(factors, [3]float64{factor[0] * scale, factor[1] * scale, factor[2] * scale})
+		}
+	}
+
+	var hash strings.Builder
+	encode83(&hash, (xComponents-1)+(yComponents-1)*9, 1)
+
+	dc, ac := factors[0], factors[1:]
+	maximum := 1.0
+	if len(ac) > 0 {
+		actualMaximum := 0.0
+		for _, factor := range ac {
+			for _, v := range factor {
+				actualMaximum = math.Max(actualMaximum, math.Abs(v))
+			}
+		}
+		quantised := int(math.Max(0, math.Min(82, math.Floor(actualMaximum*166-0.5))))
+		maximum = float64(quantised+1) / 166
+		encode83(&hash, quantised, 1)
+	} else {
+		encode83(&hash, 0, 1)
+	}
+
+	encode83(&hash, linearToSRGB(dc[0])<<16|linearToSRGB(dc[1])<<8|linearToSRGB(dc[2]), 4)
+	for _, factor := range ac {
+		quantise := func(v float64) int {
+			return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maximum, 0.5)*9+9.5))))
+		}
+		encode83(&hash, quantise(factor[0])*19*19+quantise(factor[1])*19+quantise(factor[2]), 2)
+	}
+	return hash.String()
+}
+
+const base83 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// encode83 writes value as length base-83 digits.
+func encode83(b *strings.Builder, value, length int) {
+	divisor := 1
+	for i := 1; i < length; i++ {
+		divisor *= 83
+	}
+	for ; divisor > 0; divisor /= 83 {
+		b.WriteByte(base83[value/divisor%83])
+	}
+}
+
+func srgbToLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) int {
+	v = math.Max(0, math.Min(1, v))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
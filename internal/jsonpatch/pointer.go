@@ -0,0 +1,98 @@
+package jsonpatch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePointer splits a JSON Pointer (RFC 6901) into unescaped tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses token as an index into an array of length n. When
+// appendOK is set, "-" and n refer to the position after the last element.
+func arrayIndex(token string, n int, appendOK bool) (int, error) {
+	if appendOK && token == "-" {
+		return n, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrPathNotFound, token)
+	}
+	if i > n || (i == n && !appendOK) {
+		return 0, fmt.Errorf("%w: array index %d out of range", ErrPathNotFound, i)
+	}
+	return i, nil
+}
+
+// get returns the value at tokens within doc.
+func get(doc interface{}, tokens []string) (interface{}, error) {
+	current := doc
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q", ErrPathNotFound, token)
+			}
+			current = value
+		case []interface{}:
+			i, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			current = node[i]
+		default:
+			return nil, fmt.Errorf("%w: %q is not a container", ErrPathNotFound, token)
+		}
+	}
+	return current, nil
+}
+
+// update replaces the container holding the last token with the result of
+// fn, rebuilding parents so slices can grow or shrink. It returns the new
+// document.
+func update(doc interface{}, tokens []string, fn func(parent interface{}, last string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return fn(doc, tokens[0])
+	}
+
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		child, ok := node[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: member %q", ErrPathNotFound, tokens[0])
+		}
+		updated, err := update(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[tokens[0]] = updated
+		return node, nil
+	case []interface{}:
+		i, err := arrayIndex(tokens[0], len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := update(node[i], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = updated
+		return node, nil
+	default:
+		return nil, fmt.Errorf("%w: %q is not a container", ErrPathNotFound, tokens[0])
+	}
+}
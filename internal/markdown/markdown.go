@@ -0,0 +1,152 @@
+// Package markdown renders Markdown to sanitized HTML, with a plain-text
+// excerpt and reading time.
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+const (
+	// ExcerptLength is the most characters an excerpt has.
+	ExcerptLength = 200
+	// WordsPerMinute is the reading speed reading times are based on.
+	WordsPerMinute = 200
+)
+
+// Document is rendered Markdown.
+type Document struct {
+	HTML    string
+	Excerpt string
+	// ReadingTime is in whole minutes, at least 1 for any text.
+	ReadingTime int
+}
+
+// CommonMark with the GitHub extensions: tables, strikethrough, autolinks
+// and task lists. Raw HTML in the source is dropped by the renderer, and
+// the output is sanitized again as a second line of defence.
+var converter = goldmark.New(
+	goldmark.WithExtensions(
+		extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+		extension.Strikethrough,
+		extension.Linkify,
+		extension.TaskList,
+	),
+)
+
+var policy = newPolicy()
+
+// newPolicy allows the elements of user-generated content, plus the
+// language class of fenced code blocks and the checkboxes of task lists.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	return p
+}
+
+// Render converts Markdown source to a Document.
+func Render(source string) (Document, error) {
+	src := []byte(source)
+	root := converter.Parser().Parse(text.NewReader(src))
+
+	var html bytes.Buffer
+	if err := converter.Renderer().Render(&html, src, root); err != nil {
+		return Document{}, err
+	}
+
+	prose, words := plainText(root, src)
+	return Document{
+		HTML:        policy.Sanitize(html.String()),
+		Excerpt:     excerpt(prose),
+		ReadingTime: readingTime(words),
+	}, nil
+}
+
+// plainText returns the text of the document's prose, for the excerpt, and
+// the number of words in all of it, tables and code included.
+func plainText(root ast.Node, src []byte) (prose string, words int) {
+	// Tables read poorly as running text, so their text is kept apart.
+	var text, tables strings.Builder
+	b := &text
+	ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			if n.Kind() == east.KindTable {
+				b = &text
+			}
+			if n.Type() == ast.TypeBlock {
+				b.WriteByte(' ')
+			}
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			words += countWords(n.Lines(), src)
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *east.Table:
+			b = &tables
+		case *ast.Text:
+			b.Write(n.Segment.Value(src))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(n.Value)
+		case *ast.AutoLink:
+			b.Write(n.Label(src))
+		}
+		return ast.WalkContinue, nil
+	})
+
+	prose = strings.Join(strings.Fields(text.String()), " ")
+	return prose, words + len(strings.Fields(prose)) + len(strings.Fields(tables.String()))
+}
+
+// countWords counts the words in lines of src.
+func countWords(lines *text.Segments, src []byte) int {
+	n := 0
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		n += len(strings.Fields(string(segment.Value(src))))
+	}
+	return n
+}
+
+// excerpt cuts s to at most ExcerptLength characters at a word boundary,
+// marking the cut with an ellipsis.
+func excerpt(s string) string {
+	if utf8.RuneCountInString(s) <= ExcerptLength {
+		return s
+	}
+	runes := []rune(s)[:ExcerptLength]
+	cut := len(runes)
+	for i := len(runes) - 1; i > ExcerptLength/2; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
+
+// readingTime returns the minutes it takes to read words, rounded up.
+func readingTime(words int) int {
+	return (words + WordsPerMinute - 1) / WordsPerMinute
+}
@@ -1,21 +1,53 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+
+	"portfolio-backend/internal/models"
 )
 
+// mfaChallengeAudience marks tokens that only prove the password step of a
+// two-factor login. They are rejected by AuthMiddleware.
+const mfaChallengeAudience = "mfa-challenge"
+
 type Claims struct {
 	Username string `json:"username"`
+	Role     string `json:"role"`
 	jwt.RegisteredClaims
 }
 
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// RevocationChecker reports whether an access token has been revoked before
+// its expiry, either individually by jti or along with all of its user's
+// sessions.
+type RevocationChecker interface {
+	IsRevoked(claims *Claims) (bool, error)
+}
+
+// ErrInvalidAPIKey is returned by an APIKeyAuthenticator for unknown,
+// revoked or expired keys.
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// APIKeyAuthenticator resolves the key sent in the X-API-Key header.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(key string) (*models.APIKey, error)
+}
+
+// AuthMiddleware accepts either a Bearer JWT in the Authorization header or,
+// if apiKeys is set, an API key in the X-API-Key header.
+func AuthMiddleware(keys *KeySet, revocations RevocationChecker, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" && apiKeys != nil {
+			authenticateAPIKey(c, apiKeys, apiKey)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
@@ -30,9 +62,7 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
+		token, err := keys.Parse(tokenString, &Claims{})
 
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
@@ -41,27 +71,99 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		}
 
 		claims, ok := token.Claims.(*Claims)
-		if !ok {
+		if !ok || slices.Contains(claims.Audience, mfaChallengeAudience) {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
 			c.Abort()
 			return
 		}
 
+		if revocations != nil {
+			revoked, err := revocations.IsRevoked(claims)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate token"})
+				c.Abort()
+				return
+			}
+			if revoked {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Set("auth_method", "jwt")
 		c.Set("username", claims.Username)
+		c.Set("role", claims.Role)
+		c.Set("jti", claims.ID)
+		if claims.ExpiresAt != nil {
+			c.Set("token_expires_at", claims.ExpiresAt.Time)
+		}
 		c.Next()
 	}
 }
 
-func GenerateToken(username, jwtSecret string, expiry time.Duration) (string, error) {
+func authenticateAPIKey(c *gin.Context, apiKeys APIKeyAuthenticator, apiKey string) {
+	key, err := apiKeys.AuthenticateAPIKey(apiKey)
+	if errors.Is(err, ErrInvalidAPIKey) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		c.Abort()
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate API key"})
+		c.Abort()
+		return
+	}
+
+	c.Set("auth_method", "api_key")
+	c.Set("username", "api-key:"+key.Name)
+	c.Set("api_key_id", key.ID.Hex())
+	c.Set("scopes", key.Scopes)
+	c.Next()
+}
+
+func GenerateToken(username, role, tokenID string, keys *KeySet, expiry time.Duration) (string, error) {
 	claims := &Claims{
 		Username: username,
+		Role:     role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(jwtSecret))
+	return keys.Sign(claims)
+}
+
+// GenerateMFAChallengeToken issues the short-lived token returned after the
+// password step of a login for users with two-factor authentication enabled.
+func GenerateMFAChallengeToken(username string, keys *KeySet, expiry time.Duration) (string, error) {
+	claims := &Claims{
+		Username: username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{mfaChallengeAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return keys.Sign(claims)
+}
+
+// ParseMFAChallengeToken validates a token from GenerateMFAChallengeToken and
+// returns the username it was issued for.
+func ParseMFAChallengeToken(tokenString string, keys *KeySet) (string, error) {
+	token, err := keys.Parse(tokenString, &Claims{}, jwt.WithAudience(mfaChallengeAudience))
+	if err != nil || !token.Valid {
+		return "", errors.New("invalid MFA challenge token")
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || claims.Username == "" {
+		return "", errors.New("invalid MFA challenge token")
+	}
+	return claims.Username, nil
 }
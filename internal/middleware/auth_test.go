@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func authRouter(keys *KeySet) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", AuthMiddleware(keys, nil, nil), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("username"))
+	})
+	return router
+}
+
+func authenticate(router *gin.Engine, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthMiddlewareAcceptsAccessToken(t *testing.T) {
+	keys := NewHMACKeySet("test-secret")
+	token, err := GenerateToken("admin", "owner", "jti", keys, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := authenticate(authRouter(keys), token)
+	if rec.Code != http.StatusOK || rec.Body.String() != "admin" {
+		t.Errorf("got %d %s, want 200 admin", rec.Code, rec.Body)
+	}
+}
+
+func TestAuthMiddlewareRejectsMFAChallengeToken(t *testing.T) {
+	keys := NewHMACKeySet("test-secret")
+	token, err := GenerateMFAChallengeToken("admin", keys, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := authenticate(authRouter(keys), token); rec.Code != http.StatusUnauthorized {
+		t.Errorf("got %d, want 401", rec.Code)
+	}
+
+	// The challenge token is still good for the second login step.
+	username, err := ParseMFAChallengeToken(token, keys)
+	if err != nil || username != "admin" {
+		t.Errorf("ParseMFAChallengeToken = %q, %v", username, err)
+	}
+}
+
+func TestParseMFAChallengeTokenRejectsAccessToken(t *testing.T) {
+	keys := NewHMACKeySet("test-secret")
+	token, err := GenerateToken("admin", "owner", "jti", keys, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseMFAChallengeToken(token, keys); err == nil {
+		t.Error("ParseMFAChallengeToken accepted an access token")
+	}
+}
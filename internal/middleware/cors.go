@@ -12,8 +12,9 @@ func CORSMiddleware(allowedOrigins string) gin.HandlerFunc {
 
 	config := cors.DefaultConfig()
 	config.AllowOrigins = origins
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "If-Match", "If-None-Match"}
+	config.ExposeHeaders = []string{"ETag"}
 	config.AllowCredentials = true
 
 	return cors.New(config)
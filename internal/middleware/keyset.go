@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is one entry of a KeySet. Keys loaded from a public key file can
+// only verify tokens; they are kept around so tokens signed before a rotation
+// remain valid until they expire.
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+func (k *SigningKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+// KeySet holds the keys used to sign and verify JWTs. Tokens are signed with
+// the active key and carry its id in the kid header; any key in the set can
+// verify.
+type KeySet struct {
+	keys   map[string]*SigningKey
+	active *SigningKey
+}
+
+// NewHMACKeySet returns a key set with a single HS256 secret. Tokens signed
+// with it have no kid header, matching tokens issued before key sets existed.
+func NewHMACKeySet(secret string) *KeySet {
+	key := &SigningKey{
+		Algorithm: jwt.SigningMethodHS256.Alg(),
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+	return &KeySet{
+		keys:   map[string]*SigningKey{"": key},
+		active: key,
+	}
+}
+
+// LoadKeySet reads every *.pem file in dir. The file name without extension
+// is used as the kid. Private keys may be RSA (PKCS#1 or PKCS#8) or Ed25519
+// (PKCS#8); public keys (PKIX) are loaded as verify-only. activeKID selects
+// the signing key; if empty, the private key with the greatest kid is used,
+// so date-based names such as 2026-01-15.pem rotate naturally.
+func LoadKeySet(dir, activeKID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.pem keys found in %s", dir)
+	}
+
+	ks := &KeySet{keys: make(map[string]*SigningKey)}
+	var signingIDs []string
+	for _, path := range paths {
+		kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		key, err := loadKeyFile(path, kid)
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", path, err)
+		}
+		ks.keys[kid] = key
+		if key.CanSign() {
+			signingIDs = append(signingIDs, kid)
+		}
+	}
+
+	if activeKID == "" {
+		if len(signingIDs) == 0 {
+			return nil, errors.New("key set has no private keys to sign with")
+		}
+		sort.Strings(signingIDs)
+		activeKID = signingIDs[len(signingIDs)-1]
+	}
+
+	active, ok := ks.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("active key %q not found", activeKID)
+	}
+	if !active.CanSign() {
+		return nil, fmt.Errorf("active key %q is public only", activeKID)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+func loadKeyFile(path, kid string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{ID: kid, Algorithm: jwt.SigningMethodRS256.Alg(), signKey: k, verifyKey: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return &SigningKey{ID: kid, Algorithm: jwt.SigningMethodRS256.Alg(), verifyKey: k}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: kid, Algorithm: jwt.SigningMethodEdDSA.Alg(), signKey: k, verifyKey: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &SigningKey{ID: kid, Algorithm: jwt.SigningMethodEdDSA.Alg(), verifyKey: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+// Sign signs the claims with the active key.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(ks.active.Algorithm), claims)
+	if ks.active.ID != "" {
+		token.Header["kid"] = ks.active.ID
+	}
+	return token.SignedString(ks.active.signKey)
+}
+
+// Keyfunc resolves the verification key from the token's kid header and
+// rejects tokens whose alg does not match that key.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+	return key.verifyKey, nil
+}
+
+// Algorithms lists the signing algorithms present in the set.
+func (ks *KeySet) Algorithms() []string {
+	seen := make(map[string]bool)
+	var algs []string
+	for _, key := range ks.keys {
+		if !seen[key.Algorithm] {
+			seen[key.Algorithm] = true
+			algs = append(algs, key.Algorithm)
+		}
+	}
+	sort.Strings(algs)
+	return algs
+}
+
+// Parse verifies tokenString against the set and decodes it into claims.
+func (ks *KeySet) Parse(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	opts = append(opts, jwt.WithValidMethods(ks.Algorithms()))
+	return jwt.ParseWithClaims(tokenString, claims, ks.Keyfunc, opts...)
+}
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the set. Symmetric keys are never published.
+func (ks *KeySet) JWKS() JWKS {
+	ids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		ids = append(ids, kid)
+	}
+	sort.Strings(ids)
+
+	jwks := JWKS{Keys: []JWK{}}
+	for _, kid := range ids {
+		key := ks.keys[kid]
+		switch pub := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     kid,
+				Use:       "sig",
+				Algorithm: key.Algorithm,
+				N:         base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     kid,
+				Use:       "sig",
+				Algorithm: key.Algorithm,
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	return jwks
+}
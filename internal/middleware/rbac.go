@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"portfolio-backend/internal/models"
+)
+
+// RequireRole allows the request through only if the authenticated user has
+// one of the given roles. It must run after AuthMiddleware.
+func RequireRole(roles ...models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := models.Role(c.GetString("role"))
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		c.Abort()
+	}
+}
+
+// RequirePermission allows the request through only if the authenticated
+// user's role, or the API key's scopes, grant the permission. It must run
+// after AuthMiddleware.
+func RequirePermission(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasPermission(c, permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func hasPermission(c *gin.Context, permission models.Permission) bool {
+	if scopes, ok := c.Get("scopes"); ok {
+		for _, scope := range scopes.([]models.Permission) {
+			if scope == permission {
+				return true
+			}
+		}
+		return false
+	}
+
+	return models.Role(c.GetString("role")).Has(permission)
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// projectTechnologiesArray replaces missing or null technologies with an
+// empty array, so technology filters and the validator see a consistent
+// type.
+var projectTechnologiesArray = Migration{
+	Version:     1,
+	Description: "store project technologies as an array",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("projects").UpdateMany(ctx,
+			bson.M{"$or": bson.A{
+				bson.M{"technologies": bson.M{"$exists": false}},
+				bson.M{"technologies": nil},
+			}},
+			bson.M{"$set": bson.M{"technologies": bson.A{}}},
+		)
+		return err
+	},
+	// Empty arrays are valid in every earlier version; nothing to revert.
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return nil
+	},
+}
@@ -0,0 +1,70 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// projectPositions gives projects without a manual position one that follows
+// their creation order, newest first, so sorting by position is meaningful
+// before an admin reorders anything.
+var projectPositions = Migration{
+	Version:     2,
+	Description: "backfill project positions from creation order",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		projects := db.Collection("projects")
+		cursor, err := projects.Find(ctx,
+			bson.M{"$or": bson.A{
+				bson.M{"position": bson.M{"$exists": false}},
+				bson.M{"position": 0},
+			}},
+			options.Find().
+				SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+				SetProjection(bson.M{"_id": 1}),
+		)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		var docs []struct {
+			ID interface{} `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return err
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+
+		// Place them after any project that already has a position.
+		var last struct {
+			Position float64 `bson:"position"`
+		}
+		err = projects.FindOne(ctx, bson.M{"position": bson.M{"$gt": 0}},
+			options.FindOne().SetSort(bson.D{{Key: "position", Value: -1}})).Decode(&last)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return err
+		}
+
+		models := make([]mongo.WriteModel, 0, len(docs))
+		for i, doc := range docs {
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": doc.ID}).
+				SetUpdate(bson.M{"$set": bson.M{"position": last.Position + float64(i+1)}}))
+		}
+		_, err = projects.BulkWrite(ctx, models)
+		return err
+	},
+	// Down removes every position, including ones set by hand since.
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("projects").UpdateMany(ctx,
+			bson.M{"position": bson.M{"$exists": true}},
+			bson.M{"$unset": bson.M{"position": ""}},
+		)
+		return err
+	},
+}
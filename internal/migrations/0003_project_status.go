@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// projectStatus publishes every project that predates publication states,
+// since they were all public until then.
+var projectStatus = Migration{
+	Version:     3,
+	Description: "mark existing projects as published",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("projects").UpdateMany(ctx,
+			bson.M{"status": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"status": "published"}},
+		)
+		return err
+	},
+	// Down removes the status and schedule of every project, including ones
+	// set by hand since.
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("projects").UpdateMany(ctx,
+			bson.M{},
+			bson.M{"$unset": bson.M{"status": "", "publish_at": "", "unpublish_at": ""}},
+		)
+		return err
+	},
+}
@@ -0,0 +1,84 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"portfolio-backend/internal/slug"
+)
+
+// projectSlugs derives a slug from the title of every project without one,
+// oldest first, so earlier projects keep the unsuffixed slugs.
+var projectSlugs = Migration{
+	Version:     4,
+	Description: "backfill project slugs from titles",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		projects := db.Collection("projects")
+		taken := make(map[string]bool)
+		existing, err := projects.Distinct(ctx, "slug", bson.M{"slug": bson.M{"$exists": true}})
+		if err != nil {
+			return err
+		}
+		for _, value := range existing {
+			if s, ok := value.(string); ok {
+				taken[s] = true
+			}
+		}
+
+		cursor, err := projects.Find(ctx,
+			bson.M{"$or": bson.A{
+				bson.M{"slug": bson.M{"$exists": false}},
+				bson.M{"slug": ""},
+			}},
+			options.Find().
+				SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+				SetProjection(bson.M{"_id": 1, "title": 1}),
+		)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		var docs []struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Title string             `bson:"title"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return err
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+
+		models := make([]mongo.WriteModel, 0, len(docs))
+		for _, doc := range docs {
+			base := slug.Make(doc.Title)
+			if base == "" {
+				base = doc.ID.Hex()
+			}
+			candidate := base
+			for n := 2; taken[candidate]; n++ {
+				candidate = slug.WithSuffix(base, n)
+			}
+			taken[candidate] = true
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": doc.ID}).
+				SetUpdate(bson.M{"$set": bson.M{"slug": candidate}}))
+		}
+		_, err = projects.BulkWrite(ctx, models)
+		return err
+	},
+	// Down removes every slug and slug history, including slugs set by hand
+	// since.
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("projects").UpdateMany(ctx,
+			bson.M{},
+			bson.M{"$unset": bson.M{"slug": "", "previous_slugs": ""}},
+		)
+		return err
+	},
+}
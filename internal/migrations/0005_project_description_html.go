@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"portfolio-backend/internal/markdown"
+)
+
+// projectDescriptionHTML renders the description of every project, which
+// becomes Markdown, to HTML with an excerpt and reading time.
+var projectDescriptionHTML = Migration{
+	Version:     5,
+	Description: "render project descriptions from Markdown",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		projects := db.Collection("projects")
+		cursor, err := projects.Find(ctx,
+			bson.M{"description_html": bson.M{"$exists": false}},
+			options.Find().SetProjection(bson.M{"_id": 1, "description": 1}),
+		)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		var models []mongo.WriteModel
+		for cursor.Next(ctx) {
+			var doc struct {
+				ID          primitive.ObjectID `bson:"_id"`
+				Description string             `bson:"description"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return err
+			}
+			rendered, err := markdown.Render(doc.Description)
+			if err != nil {
+				return err
+			}
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": doc.ID}).
+				SetUpdate(bson.M{"$set": bson.M{
+					"description_html": rendered.HTML,
+					"excerpt":          rendered.Excerpt,
+					"reading_time":     rendered.ReadingTime,
+				}}))
+		}
+		if err := cursor.Err(); err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			return nil
+		}
+		_, err = projects.BulkWrite(ctx, models)
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("projects").UpdateMany(ctx,
+			bson.M{},
+			bson.M{"$unset": bson.M{"description_html": "", "excerpt": "", "reading_time": ""}},
+		)
+		return err
+	},
+}
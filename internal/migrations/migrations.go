@@ -0,0 +1,285 @@
+// Package migrations applies versioned data migrations to the MongoDB
+// database. Applied versions are recorded in the schema_migrations
+// collection, and a lock document keeps concurrent instances from migrating
+// at the same time.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	migrationsCollection = "schema_migrations"
+	lockCollection       = "schema_migrations_lock"
+	lockID               = "migrate"
+	// lockTTL bounds how long a crashed migrator can block others.
+	lockTTL = 10 * time.Minute
+	// lockRefreshInterval is how often a running migrator extends its lock.
+	lockRefreshInterval = lockTTL / 4
+)
+
+var (
+	// ErrLocked is returned when another instance holds the migration lock.
+	ErrLocked = errors.New("migrations are locked by another instance")
+	// ErrLockLost is returned when another instance took the migration lock
+	// over while migrations were running.
+	ErrLockLost = errors.New("migration lock was lost to another instance")
+	// ErrUnknownVersion is returned for a target version that is not registered.
+	ErrUnknownVersion = errors.New("unknown migration version")
+)
+
+// Migration is one ordered change to the data. Up and Down must be
+// idempotent: a migration interrupted half way is run again from the start.
+type Migration struct {
+	Version     int64
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+	Down        func(ctx context.Context, db *mongo.Database) error
+}
+
+// Record is the schema_migrations document of an applied migration.
+type Record struct {
+	Version     int64     `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// Status describes a registered migration and whether it has been applied.
+type Status struct {
+	Version     int64
+	Description string
+	AppliedAt   *time.Time
+}
+
+type Migrator struct {
+	db         *mongo.Database
+	migrations []Migration
+	owner      string
+}
+
+// New returns a Migrator for the registered migrations.
+func New(db *mongo.Database) *Migrator {
+	return NewWithMigrations(db, registered)
+}
+
+// NewWithMigrations returns a Migrator for the given migrations, which are
+// sorted by version.
+func NewWithMigrations(db *mongo.Database, migrations []Migration) *Migrator {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	host, _ := os.Hostname()
+	return &Migrator{
+		db:         db,
+		migrations: sorted,
+		owner:      fmt.Sprintf("%s/%d", host, os.Getpid()),
+	}
+}
+
+// Status lists every registered migration in order.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Description: migration.Description}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending returns the migrations that have not been applied.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies pending migrations in order, up to and including target. A
+// target of 0 applies all of them. It returns the versions applied.
+func (m *Migrator) Up(ctx context.Context, target int64) ([]int64, error) {
+	if target != 0 && !m.known(target) {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, target)
+	}
+
+	var done []int64
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		pending, err := m.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		for _, migration := range pending {
+			if target != 0 && migration.Version > target {
+				break
+			}
+			if err := migration.Up(ctx, m.db); err != nil {
+				return fmt.Errorf("migration %d (%s): %w", migration.Version, migration.Description, err)
+			}
+			record := Record{Version: migration.Version, Description: migration.Description, AppliedAt: time.Now()}
+			opts := options.Replace().SetUpsert(true)
+			if _, err := m.db.Collection(migrationsCollection).ReplaceOne(ctx, bson.M{"_id": record.Version}, record, opts); err != nil {
+				return err
+			}
+			done = append(done, migration.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down reverts the last steps applied migrations, newest first. It returns
+// the versions reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int64, error) {
+	var done []int64
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == nil {
+				return fmt.Errorf("migration %d (%s) cannot be reverted", migration.Version, migration.Description)
+			}
+			if err := migration.Down(ctx, m.db); err != nil {
+				return fmt.Errorf("migration %d (%s): %w", migration.Version, migration.Description, err)
+			}
+			if _, err := m.db.Collection(migrationsCollection).DeleteOne(ctx, bson.M{"_id": migration.Version}); err != nil {
+				return err
+			}
+			done = append(done, migration.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+func (m *Migrator) known(version int64) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]Record, error) {
+	cursor, err := m.db.Collection(migrationsCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]Record, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// withLock runs fn while holding the migration lock. The lock is a single
+// document that can only be taken over once it has expired; it is extended
+// while fn runs, and fn's context is cancelled if it is lost all the same.
+func (m *Migrator) withLock(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	locks := m.db.Collection(lockCollection)
+	now := time.Now()
+
+	_, err = locks.UpdateOne(ctx,
+		bson.M{"_id": lockID, "expires_at": bson.M{"$lt": now}},
+		bson.M{"$set": bson.M{"owner": m.owner, "locked_at": now, "expires_at": now.Add(lockTTL)}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// The lock document exists and has not expired.
+		return ErrLocked
+	}
+	if err != nil {
+		return err
+	}
+
+	lockCtx, cancel := context.WithCancelCause(ctx)
+	refreshed := make(chan struct{})
+	go func() {
+		defer close(refreshed)
+		m.refreshLock(lockCtx, cancel)
+	}()
+
+	defer func() {
+		cancel(nil)
+		<-refreshed
+
+		// Release even if ctx was cancelled during the migration.
+		releaseCtx, cancelRelease := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancelRelease()
+		if _, releaseErr := locks.DeleteOne(releaseCtx, bson.M{"_id": lockID, "owner": m.owner}); releaseErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to release the migration lock: %w", releaseErr))
+		}
+	}()
+
+	if err := fn(lockCtx); err != nil {
+		if errors.Is(context.Cause(lockCtx), ErrLockLost) {
+			return fmt.Errorf("%w: %v", ErrLockLost, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// refreshLock extends the lock every lockRefreshInterval until ctx is done,
+// so migrations running longer than lockTTL keep it. Failed refreshes are
+// retried on the next tick, well before the lock expires; if another
+// instance has taken the lock over, ctx is cancelled with ErrLockLost.
+func (m *Migrator) refreshLock(ctx context.Context, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(lockRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		result, err := m.db.Collection(lockCollection).UpdateOne(ctx,
+			bson.M{"_id": lockID, "owner": m.owner},
+			bson.M{"$set": bson.M{"expires_at": time.Now().Add(lockTTL)}},
+		)
+		if err == nil && result.MatchedCount == 0 {
+			cancel(ErrLockLost)
+			return
+		}
+	}
+}
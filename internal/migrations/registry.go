@@ -0,0 +1,11 @@
+package migrations
+
+// registered lists every migration. Append new migrations with the next
+// version number; never renumber or remove applied ones.
+var registered = []Migration{
+	projectTechnologiesArray,
+	projectPositions,
+	projectStatus,
+	projectSlugs,
+	projectDescriptionHTML,
+}
@@ -14,6 +14,9 @@ type Contact struct {
 	Message   string             `json:"message" bson:"message" binding:"required"`
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
 	Read      bool               `json:"read" bson:"read"`
+	Version   int64              `json:"version" bson:"version"`
+	// DeletedAt is set while the contact is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
 }
 
 type ContactResponse struct {
@@ -24,4 +27,20 @@ type ContactResponse struct {
 	Message   string             `json:"message"`
 	CreatedAt time.Time          `json:"created_at"`
 	Read      bool               `json:"read"`
+	Version   int64              `json:"version"`
+	DeletedAt *time.Time         `json:"deleted_at,omitempty"`
+}
+
+func (c *Contact) ToResponse() ContactResponse {
+	return ContactResponse{
+		ID:        c.ID,
+		Name:      c.Name,
+		Email:     c.Email,
+		Subject:   c.Subject,
+		Message:   c.Message,
+		CreatedAt: c.CreatedAt,
+		Read:      c.Read,
+		Version:   c.Version,
+		DeletedAt: c.DeletedAt,
+	}
 }
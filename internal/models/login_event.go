@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reasons recorded on login events.
+const (
+	LoginReasonSuccess            = "success"
+	LoginReasonMFARequired        = "mfa_required"
+	LoginReasonInvalidCredentials = "invalid_credentials"
+	LoginReasonInvalidMFACode     = "invalid_mfa_code"
+	LoginReasonDisabled           = "disabled"
+	LoginReasonLocked             = "locked"
+)
+
+// LoginEvent is one entry of the login audit trail.
+type LoginEvent struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Username  string             `json:"username" bson:"username"`
+	IP        string             `json:"ip" bson:"ip"`
+	UserAgent string             `json:"user_agent" bson:"user_agent"`
+	Success   bool               `json:"success" bson:"success"`
+	Reason    string             `json:"reason" bson:"reason"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
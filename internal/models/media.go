@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Media is an uploaded image. The file itself lives in the media storage
+// under Key; the document holds its metadata.
+type Media struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Key         string             `json:"-" bson:"key"`
+	Filename    string             `json:"filename" bson:"filename"`
+	ContentType string             `json:"content_type" bson:"content_type"`
+	Size        int64              `json:"size" bson:"size"`
+	Width       int                `json:"width" bson:"width"`
+	Height      int                `json:"height" bson:"height"`
+	SHA256      string             `json:"sha256" bson:"sha256"`
+	Alt         string             `json:"alt" bson:"alt"`
+	UploadedBy  string             `json:"uploaded_by" bson:"uploaded_by"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	// URL is where the file is served from. It depends on the server's
+	// configuration, so it is filled in when media is returned, not stored.
+	URL string `json:"url" bson:"-"`
+	// Variants are resized copies of the image, narrowest first.
+	Variants []MediaVariant `json:"variants" bson:"variants,omitempty"`
+	// Blurhash and DominantColor are placeholders to show while the image
+	// loads; DominantColor is the average color as #rrggbb.
+	Blurhash      string `json:"blurhash" bson:"blurhash,omitempty"`
+	DominantColor string `json:"dominant_color" bson:"dominant_color,omitempty"`
+}
+
+// Names of the media variants, from narrowest to widest.
+const (
+	MediaVariantThumbnail = "thumbnail"
+	MediaVariantMedium    = "medium"
+	MediaVariantLarge     = "large"
+)
+
+// MediaVariant is a resized and re-encoded copy of an uploaded image,
+// stored next to the original.
+type MediaVariant struct {
+	Name        string `json:"name" bson:"name"`
+	Key         string `json:"-" bson:"key"`
+	ContentType string `json:"content_type" bson:"content_type"`
+	Size        int64  `json:"size" bson:"size"`
+	Width       int    `json:"width" bson:"width"`
+	Height      int    `json:"height" bson:"height"`
+	SHA256      string `json:"sha256" bson:"sha256"`
+	URL         string `json:"url" bson:"-"`
+}
+
+// MediaResponse is the public view of media, as shown in project galleries.
+type MediaResponse struct {
+	ID          primitive.ObjectID `json:"id"`
+	URL         string             `json:"url"`
+	Alt         string             `json:"alt"`
+	ContentType string             `json:"content_type"`
+	Width       int                `json:"width"`
+	Height      int                `json:"height"`
+	// Variants lists the resized copies and the original, narrowest first,
+	// and Srcset joins them into a value for an <img> srcset attribute.
+	Variants      []MediaVariantResponse `json:"variants"`
+	Srcset        string                 `json:"srcset"`
+	Blurhash      string                 `json:"blurhash,omitempty"`
+	DominantColor string                 `json:"dominant_color,omitempty"`
+}
+
+type MediaVariantResponse struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// MediaVariantOriginal names the original upload among the variants of a
+// MediaResponse.
+const MediaVariantOriginal = "original"
+
+func (m *Media) ToResponse() MediaResponse {
+	variants := make([]MediaVariantResponse, 0, len(m.Variants)+1)
+	for _, v := range m.Variants {
+		variants = append(variants, MediaVariantResponse{Name: v.Name, URL: v.URL, Width: v.Width, Height: v.Height})
+	}
+	variants = append(variants, MediaVariantResponse{Name: MediaVariantOriginal, URL: m.URL, Width: m.Width, Height: m.Height})
+
+	srcset := make([]string, len(variants))
+	for i, v := range variants {
+		srcset[i] = fmt.Sprintf("%s %dw", v.URL, v.Width)
+	}
+
+	return MediaResponse{
+		ID:            m.ID,
+		URL:           m.URL,
+		Alt:           m.Alt,
+		ContentType:   m.ContentType,
+		Width:         m.Width,
+		Height:        m.Height,
+		Variants:      variants,
+		Srcset:        strings.Join(srcset, ", "),
+		Blurhash:      m.Blurhash,
+		DominantColor: m.DominantColor,
+	}
+}
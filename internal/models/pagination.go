@@ -0,0 +1,13 @@
+package models
+
+// Pagination is the metadata returned alongside a page of list results.
+// Page and TotalPages are only set for page-based requests; NextCursor is set
+// whenever more results follow.
+type Pagination struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Page       int    `json:"page,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Post is a blog article. It is a draft while PublishedAt is unset and
+// public from PublishedAt on.
+type Post struct {
+	ID           primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Title        string              `json:"title" bson:"title" binding:"required"`
+	Slug         string              `json:"slug" bson:"slug"`
+	Body         string              `json:"body" bson:"body" binding:"required"` // Markdown
+	Tags         []string            `json:"tags" bson:"tags"`
+	CoverMediaID *primitive.ObjectID `json:"cover_media_id" bson:"cover_media_id,omitempty"`
+	Author       string              `json:"author" bson:"author"`
+	PublishedAt  *time.Time          `json:"published_at" bson:"published_at,omitempty"`
+	CreatedAt    time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at" bson:"updated_at"`
+	// Version is incremented on every write and used for optimistic
+	// concurrency control.
+	Version int64 `json:"version" bson:"version"`
+	// BodyHTML, Excerpt and ReadingTime (in minutes) are rendered from the
+	// Markdown body whenever the post is written.
+	BodyHTML    string `json:"body_html" bson:"body_html"`
+	Excerpt     string `json:"excerpt" bson:"excerpt"`
+	ReadingTime int    `json:"reading_time" bson:"reading_time"`
+}
+
+type PostResponse struct {
+	ID           primitive.ObjectID  `json:"id"`
+	Title        string              `json:"title"`
+	Slug         string              `json:"slug"`
+	Body         string              `json:"body,omitempty"` // Markdown
+	BodyHTML     string              `json:"body_html,omitempty"`
+	Excerpt      string              `json:"excerpt"`
+	ReadingTime  int                 `json:"reading_time"`
+	Tags         []string            `json:"tags"`
+	CoverMediaID *primitive.ObjectID `json:"cover_media_id"`
+	Cover        *MediaResponse      `json:"cover"` // filled in by the post service
+	Author       string              `json:"author"`
+	PublishedAt  *time.Time          `json:"published_at"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	Version      int64               `json:"version"`
+}
+
+func (p *Post) ToResponse() PostResponse {
+	return PostResponse{
+		ID:           p.ID,
+		Title:        p.Title,
+		Slug:         p.Slug,
+		Body:         p.Body,
+		BodyHTML:     p.BodyHTML,
+		Excerpt:      p.Excerpt,
+		ReadingTime:  p.ReadingTime,
+		Tags:         p.Tags,
+		CoverMediaID: p.CoverMediaID,
+		Author:       p.Author,
+		PublishedAt:  p.PublishedAt,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+		Version:      p.Version,
+	}
+}
+
+// SelectFormat keeps only the representation of the body named by format,
+// one of Formats; an empty format keeps both.
+func (r *PostResponse) SelectFormat(format string) {
+	switch format {
+	case FormatMarkdown:
+		r.BodyHTML = ""
+	case FormatHTML:
+		r.Body = ""
+	}
+}
+
+// IsPublic reports whether the post is visible to the public at t.
+func (p *Post) IsPublic(t time.Time) bool {
+	return p.PublishedAt != nil && !p.PublishedAt.After(t)
+}
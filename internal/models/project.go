@@ -6,9 +6,31 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Project publication states. Only published projects are public, and only
+// within their publish_at/unpublish_at window.
+const (
+	ProjectStatusDraft     = "draft"
+	ProjectStatusPublished = "published"
+	ProjectStatusArchived  = "archived"
+)
+
+// ProjectStatuses lists the valid project states.
+var ProjectStatuses = []string{ProjectStatusDraft, ProjectStatusPublished, ProjectStatusArchived}
+
+// Representations of Markdown content that clients can ask for. Responses
+// carry both unless one is chosen.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Formats lists the valid content representations.
+var Formats = []string{FormatMarkdown, FormatHTML}
+
 type Project struct {
 	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	Title        string             `json:"title" bson:"title" binding:"required"`
+	Slug         string             `json:"slug" bson:"slug,omitempty"`
 	Description  string             `json:"description" bson:"description" binding:"required"`
 	ImageURL     string             `json:"image_url" bson:"image_url"`
 	LiveURL      string             `json:"live_url" bson:"live_url"`
@@ -16,20 +38,183 @@ type Project struct {
 	Technologies []string           `json:"technologies" bson:"technologies"`
 	Category     string             `json:"category" bson:"category"`
 	Featured     bool               `json:"featured" bson:"featured"`
+	Position     float64            `json:"position" bson:"position"`
+	Status       string             `json:"status" bson:"status"`
+	PublishAt    *time.Time         `json:"publish_at" bson:"publish_at,omitempty"`     // when a draft is published
+	UnpublishAt  *time.Time         `json:"unpublish_at" bson:"unpublish_at,omitempty"` // when a published project is archived
 	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+	// Version is incremented on every write and used for optimistic
+	// concurrency control.
+	Version int64 `json:"version" bson:"version"`
+	// PreviousSlugs are the project's earlier slugs, which redirect to the
+	// current one.
+	PreviousSlugs []string `json:"previous_slugs,omitempty" bson:"previous_slugs,omitempty"`
+	// MediaIDs lists the uploaded media shown in the project's gallery, in
+	// order.
+	MediaIDs []primitive.ObjectID `json:"media_ids" bson:"media_ids,omitempty"`
+	// DescriptionHTML, Excerpt and ReadingTime (in minutes) are rendered
+	// from the Markdown description whenever the project is written.
+	DescriptionHTML string `json:"description_html" bson:"description_html"`
+	Excerpt         string `json:"excerpt" bson:"excerpt"`
+	ReadingTime     int    `json:"reading_time" bson:"reading_time"`
+	// DeletedAt is set while the project is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+}
+
+// ProjectFields are the project fields clients may write. PATCH requests are
+// applied to this representation.
+type ProjectFields struct {
+	Title        string     `json:"title"`
+	Slug         string     `json:"slug"`
+	Description  string     `json:"description"`
+	ImageURL     string     `json:"image_url"`
+	LiveURL      string     `json:"live_url"`
+	GitHubURL    string     `json:"github_url"`
+	Technologies []string   `json:"technologies"`
+	Category     string     `json:"category"`
+	Featured     bool       `json:"featured"`
+	Position     float64    `json:"position"`
+	Status       string     `json:"status"`
+	PublishAt    *time.Time `json:"publish_at"`
+	UnpublishAt  *time.Time `json:"unpublish_at"`
+
+	MediaIDs []primitive.ObjectID `json:"media_ids"`
 }
 
 type ProjectResponse struct {
 	ID           primitive.ObjectID `json:"id"`
 	Title        string             `json:"title"`
-	Description  string             `json:"description"`
+	Slug         string             `json:"slug"`
+	Description  string             `json:"description,omitempty"` // Markdown
 	ImageURL     string             `json:"image_url"`
 	LiveURL      string             `json:"live_url"`
 	GitHubURL    string             `json:"github_url"`
 	Technologies []string           `json:"technologies"`
 	Category     string             `json:"category"`
 	Featured     bool               `json:"featured"`
+	Position     float64            `json:"position"`
+	Status       string             `json:"status"`
+	PublishAt    *time.Time         `json:"publish_at"`
+	UnpublishAt  *time.Time         `json:"unpublish_at"`
+	Gallery      []MediaResponse    `json:"gallery"` // filled in by the project service
 	CreatedAt    time.Time          `json:"created_at"`
 	UpdatedAt    time.Time          `json:"updated_at"`
+	Version      int64              `json:"version"`
+	DeletedAt    *time.Time         `json:"deleted_at,omitempty"`
+
+	DescriptionHTML string `json:"description_html,omitempty"`
+	Excerpt         string `json:"excerpt"`
+	ReadingTime     int    `json:"reading_time"`
+}
+
+// SelectFormat keeps only the representation of the description named by
+// format, one of Formats; an empty format keeps both.
+func (r *ProjectResponse) SelectFormat(format string) {
+	switch format {
+	case FormatMarkdown:
+		r.DescriptionHTML = ""
+	case FormatHTML:
+		r.Description = ""
+	}
+}
+
+func (p *Project) ToResponse() ProjectResponse {
+	return ProjectResponse{
+		ID:           p.ID,
+		Title:        p.Title,
+		Slug:         p.Slug,
+		Description:  p.Description,
+		ImageURL:     p.ImageURL,
+		LiveURL:      p.LiveURL,
+		GitHubURL:    p.GitHubURL,
+		Technologies: p.Technologies,
+		Category:     p.Category,
+		Featured:     p.Featured,
+		Position:     p.Position,
+		Status:       p.Status,
+		PublishAt:    p.PublishAt,
+		UnpublishAt:  p.UnpublishAt,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+		Version:      p.Version,
+		DeletedAt:    p.DeletedAt,
+
+		DescriptionHTML: p.DescriptionHTML,
+		Excerpt:         p.Excerpt,
+		ReadingTime:     p.ReadingTime,
+	}
+}
+
+// Fields returns the writable fields of the project.
+func (p *Project) Fields() ProjectFields {
+	return ProjectFields{
+		Title:        p.Title,
+		Slug:         p.Slug,
+		Description:  p.Description,
+		ImageURL:     p.ImageURL,
+		LiveURL:      p.LiveURL,
+		GitHubURL:    p.GitHubURL,
+		Technologies: p.Technologies,
+		Category:     p.Category,
+		Featured:     p.Featured,
+		Position:     p.Position,
+		Status:       p.Status,
+		PublishAt:    p.PublishAt,
+		UnpublishAt:  p.UnpublishAt,
+		MediaIDs:     p.MediaIDs,
+	}
+}
+
+// SetFields overwrites the writable fields of the project.
+func (p *Project) SetFields(f ProjectFields) {
+	p.Title = f.Title
+	p.Slug = f.Slug
+	p.Description = f.Description
+	p.ImageURL = f.ImageURL
+	p.LiveURL = f.LiveURL
+	p.GitHubURL = f.GitHubURL
+	p.Technologies = f.Technologies
+	p.Category = f.Category
+	p.Featured = f.Featured
+	p.Position = f.Position
+	p.Status = f.Status
+	p.PublishAt = f.PublishAt
+	p.UnpublishAt = f.UnpublishAt
+	p.MediaIDs = f.MediaIDs
+}
+
+// IsPublic reports whether the project is visible to the public at t: it is
+// published and t is within its publish_at/unpublish_at window.
+func (p *Project) IsPublic(t time.Time) bool {
+	return p.Status == ProjectStatusPublished &&
+		(p.PublishAt == nil || !p.PublishAt.After(t)) &&
+		(p.UnpublishAt == nil || p.UnpublishAt.After(t))
+}
+
+// ScheduledStatus returns the status the project should have at t: a draft
+// whose publish_at has passed is published, and a published project whose
+// unpublish_at has passed is archived.
+func (p *Project) ScheduledStatus(t time.Time) string {
+	status := p.Status
+	if status == ProjectStatusDraft && p.PublishAt != nil && !p.PublishAt.After(t) {
+		status = ProjectStatusPublished
+	}
+	if status == ProjectStatusPublished && p.UnpublishAt != nil && !p.UnpublishAt.After(t) {
+		status = ProjectStatusArchived
+	}
+	return status
+}
+
+// FacetCount is one distinct value of a filterable field and the number of
+// projects that have it.
+type FacetCount struct {
+	Value string `json:"value" bson:"_id"`
+	Count int64  `json:"count" bson:"count"`
+}
+
+// ProjectFacets lists the values available for filtering projects.
+type ProjectFacets struct {
+	Categories   []FacetCount `json:"categories" bson:"categories"`
+	Technologies []FacetCount `json:"technologies" bson:"technologies"`
 }
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Project revision actions.
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+)
+
+// ProjectRevision is an immutable snapshot of a project taken on every
+// write. Number increases per project; for creates, updates and restores it
+// equals the project version the write produced.
+type ProjectRevision struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProjectID primitive.ObjectID `json:"project_id" bson:"project_id"`
+	Number    int64              `json:"number" bson:"number"`
+	Action    string             `json:"action" bson:"action"`
+	Author    string             `json:"author" bson:"author"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	// RestoredFrom is the revision number a restore copied.
+	RestoredFrom int64   `json:"restored_from,omitempty" bson:"restored_from,omitempty"`
+	Snapshot     Project `json:"snapshot" bson:"snapshot"`
+}
+
+// FieldChange is one field that differs between two revisions.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// ProjectRevisionDiff is the field-level difference between two revisions.
+type ProjectRevisionDiff struct {
+	ProjectID primitive.ObjectID `json:"project_id"`
+	From      int64              `json:"from"`
+	To        int64              `json:"to"`
+	Changes   []FieldChange      `json:"changes"`
+}
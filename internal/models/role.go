@@ -0,0 +1,85 @@
+package models
+
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleEditor Role = "editor"
+	RoleViewer Role = "viewer"
+)
+
+type Permission string
+
+const (
+	PermissionProjectsRead   Permission = "projects:read"
+	PermissionProjectsWrite  Permission = "projects:write"
+	PermissionPostsRead      Permission = "posts:read"
+	PermissionPostsWrite     Permission = "posts:write"
+	PermissionContactsRead   Permission = "contacts:read"
+	PermissionContactsWrite  Permission = "contacts:write"
+	PermissionContactsDelete Permission = "contacts:delete"
+	PermissionUsersManage    Permission = "users:manage"
+)
+
+// AllPermissions lists every permission, in the order they are declared.
+var AllPermissions = []Permission{
+	PermissionProjectsRead,
+	PermissionProjectsWrite,
+	PermissionPostsRead,
+	PermissionPostsWrite,
+	PermissionContactsRead,
+	PermissionContactsWrite,
+	PermissionContactsDelete,
+	PermissionUsersManage,
+}
+
+func (p Permission) Valid() bool {
+	for _, known := range AllPermissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+var rolePermissions = map[Role][]Permission{
+	RoleOwner: {
+		PermissionProjectsRead,
+		PermissionProjectsWrite,
+		PermissionPostsRead,
+		PermissionPostsWrite,
+		PermissionContactsRead,
+		PermissionContactsWrite,
+		PermissionContactsDelete,
+		PermissionUsersManage,
+	},
+	RoleEditor: {
+		PermissionProjectsRead,
+		PermissionProjectsWrite,
+		PermissionPostsRead,
+		PermissionPostsWrite,
+	},
+	RoleViewer: {
+		PermissionProjectsRead,
+		PermissionPostsRead,
+	},
+}
+
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Permissions returns the permissions granted to the role.
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+func (r Role) Has(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package models
+
+// ProjectSearchResult is a project matching a search query. Highlights maps
+// field names to HTML excerpts with the matched words wrapped in <mark>.
+type ProjectSearchResult struct {
+	Project    ProjectResponse   `json:"project"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// ContactSearchResult is a contact message matching a search query.
+type ContactSearchResult struct {
+	Contact    ContactResponse   `json:"contact"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
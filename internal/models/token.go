@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshToken is the server-side record of an issued refresh token. Only the
+// SHA-256 hash of the token is stored. Tokens that descend from the same login
+// share a FamilyID so the whole chain can be revoked at once.
+type RefreshToken struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	FamilyID  string             `json:"family_id" bson:"family_id"`
+	Username  string             `json:"username" bson:"username"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	RotatedAt *time.Time         `json:"rotated_at,omitempty" bson:"rotated_at,omitempty"`
+	RevokedAt *time.Time         `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+}
+
+// RevokedToken marks an access token, identified by its jti, as revoked until
+// it would have expired anyway.
+type RevokedToken struct {
+	JTI       string    `json:"jti" bson:"_id"`
+	RevokedAt time.Time `json:"revoked_at" bson:"revoked_at"`
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+}
+
+// PasswordResetToken is a single-use, time-limited token emailed to a user to
+// reset their password. Only the SHA-256 hash of the token is stored.
+type PasswordResetToken struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	Username  string             `json:"username" bson:"username"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	UsedAt    *time.Time         `json:"used_at,omitempty" bson:"used_at,omitempty"`
+}
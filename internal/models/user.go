@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type User struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Username     string             `json:"username" bson:"username"`
+	PasswordHash string             `json:"-" bson:"password_hash"`
+	DisplayName  string             `json:"display_name" bson:"display_name"`
+	Email        string             `json:"email,omitempty" bson:"email,omitempty"`
+	Role         Role               `json:"role" bson:"role"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+	LastLoginAt  *time.Time         `json:"last_login_at,omitempty" bson:"last_login_at,omitempty"`
+	Disabled     bool               `json:"disabled" bson:"disabled"`
+
+	// Access tokens issued before SessionsRevokedAt are rejected.
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty" bson:"password_changed_at,omitempty"`
+	SessionsRevokedAt *time.Time `json:"-" bson:"sessions_revoked_at,omitempty"`
+
+	// TOTP two-factor authentication. The pending secret holds an enrollment
+	// that has not been confirmed with a valid code yet. Recovery codes are
+	// stored as SHA-256 hashes and removed once used.
+	TOTPEnabled       bool     `json:"totp_enabled" bson:"totp_enabled"`
+	TOTPSecret        string   `json:"-" bson:"totp_secret,omitempty"`
+	TOTPPendingSecret string   `json:"-" bson:"totp_pending_secret,omitempty"`
+	TOTPLastStep      int64    `json:"-" bson:"totp_last_step,omitempty"`
+	RecoveryCodes     []string `json:"-" bson:"recovery_codes,omitempty"`
+}
+
+type UserResponse struct {
+	ID          primitive.ObjectID `json:"id"`
+	Username    string             `json:"username"`
+	DisplayName string             `json:"display_name"`
+	Email       string             `json:"email,omitempty"`
+	Role        Role               `json:"role"`
+	CreatedAt   time.Time          `json:"created_at"`
+	LastLoginAt *time.Time         `json:"last_login_at,omitempty"`
+	Disabled    bool               `json:"disabled"`
+	TOTPEnabled bool               `json:"totp_enabled"`
+}
+
+func (u *User) ToResponse() UserResponse {
+	return UserResponse{
+		ID:          u.ID,
+		Username:    u.Username,
+		DisplayName: u.DisplayName,
+		Email:       u.Email,
+		Role:        u.Role,
+		CreatedAt:   u.CreatedAt,
+		LastLoginAt: u.LastLoginAt,
+		Disabled:    u.Disabled,
+		TOTPEnabled: u.TOTPEnabled,
+	}
+}
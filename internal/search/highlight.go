@@ -0,0 +1,83 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+	ellipsis  = "…"
+)
+
+// Highlight returns an HTML-escaped excerpt of text of about maxLen bytes
+// around the first occurrence of any of terms, with every occurrence wrapped
+// in <mark>. It returns "" when no term occurs. terms must come from Terms.
+func Highlight(text string, terms []string, maxLen int) string {
+	wanted := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		wanted[term] = true
+	}
+
+	var matches []token
+	for _, t := range tokenize(text) {
+		if wanted[t.term] {
+			matches = append(matches, t)
+		}
+	}
+	if len(matches) == 0 {
+		return ""
+	}
+
+	start, end := 0, len(text)
+	if maxLen > 0 && len(text) > maxLen {
+		// Show a little context before the first match.
+		start = min(nextWordStart(text, max(0, matches[0].start-maxLen/4)), matches[0].start)
+		end = max(wordEnd(text, min(len(text), start+maxLen)), matches[0].end)
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString(ellipsis)
+	}
+	pos := start
+	for _, m := range matches {
+		if m.start < start {
+			continue
+		}
+		if m.end > end {
+			break
+		}
+		sb.WriteString(html.EscapeString(text[pos:m.start]))
+		sb.WriteString(markOpen)
+		sb.WriteString(html.EscapeString(text[m.start:m.end]))
+		sb.WriteString(markClose)
+		pos = m.end
+	}
+	sb.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		sb.WriteString(ellipsis)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// nextWordStart moves i forward to the start of the next word, so excerpts
+// do not begin mid-word.
+func nextWordStart(text string, i int) int {
+	if i <= 0 {
+		return 0
+	}
+	if next := strings.IndexAny(text[i:], " \t\n"); next >= 0 {
+		return i + next + 1
+	}
+	return len(text)
+}
+
+// wordEnd moves i forward to the end of the current word.
+func wordEnd(text string, i int) int {
+	if next := strings.IndexAny(text[i:], " \t\n"); next >= 0 {
+		return i + next
+	}
+	return len(text)
+}
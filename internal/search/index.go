@@ -0,0 +1,136 @@
+// Package search is a small in-process full-text index used when documents
+// are not stored in MongoDB, plus snippet highlighting for search results.
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Field is a piece of document text and its relevance weight.
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Hit is a matching document id and its relevance score.
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+// Index is an inverted index ranking documents with BM25 over weighted
+// fields. It is safe for concurrent use.
+type Index struct {
+	mutex    sync.RWMutex
+	postings map[string]map[string]float64 // term -> doc id -> weighted term frequency
+	docTerms map[string][]string           // doc id -> distinct terms, for removal
+	docLen   map[string]int
+	totalLen int
+}
+
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[string]float64),
+		docTerms: make(map[string][]string),
+		docLen:   make(map[string]int),
+	}
+}
+
+// Put indexes a document, replacing any previous version with the same id.
+func (idx *Index) Put(id string, fields ...Field) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	idx.remove(id)
+
+	frequencies := make(map[string]float64)
+	length := 0
+	for _, field := range fields {
+		for _, t := range tokenize(field.Text) {
+			frequencies[t.term] += field.Weight
+			length++
+		}
+	}
+
+	terms := make([]string, 0, len(frequencies))
+	for term, tf := range frequencies {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[string]float64)
+		}
+		idx.postings[term][id] = tf
+		terms = append(terms, term)
+	}
+	idx.docTerms[id] = terms
+	idx.docLen[id] = length
+	idx.totalLen += length
+}
+
+// Remove drops a document from the index.
+func (idx *Index) Remove(id string) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	idx.remove(id)
+}
+
+func (idx *Index) remove(id string) {
+	terms, ok := idx.docTerms[id]
+	if !ok {
+		return
+	}
+	for _, term := range terms {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	idx.totalLen -= idx.docLen[id]
+	delete(idx.docTerms, id)
+	delete(idx.docLen, id)
+}
+
+// Search returns documents matching any term of query, best first. A limit of
+// 0 returns every match.
+func (idx *Index) Search(query string, limit int) []Hit {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	n := float64(len(idx.docTerms))
+	if n == 0 {
+		return nil
+	}
+	avgLen := float64(idx.totalLen) / n
+
+	scores := make(map[string]float64)
+	for _, term := range Terms(query) {
+		docs := idx.postings[term]
+		df := float64(len(docs))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range docs {
+			norm := k1 * (1 - b + b*float64(idx.docLen[id])/avgLen)
+			scores[id] += idf * tf * (k1 + 1) / (tf + norm)
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
@@ -0,0 +1,76 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// stopWords are common English words left out of the index, as MongoDB text
+// indexes do.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "with": true,
+}
+
+// token is a normalized term and the byte range it came from.
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text into words of letters and digits, lowercases and
+// stems them, and drops stop words.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			tokens = appendToken(tokens, text, start, i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = appendToken(tokens, text, start, len(text))
+	}
+	return tokens
+}
+
+func appendToken(tokens []token, text string, start, end int) []token {
+	word := strings.ToLower(text[start:end])
+	if stopWords[word] {
+		return tokens
+	}
+	return append(tokens, token{term: stem(word), start: start, end: end})
+}
+
+// stem folds simple English plurals so "projects" matches "project".
+func stem(word string) string {
+	n := utf8.RuneCountInString(word)
+	switch {
+	case n > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case n > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// Terms returns the distinct normalized terms of a query.
+func Terms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, t := range tokenize(query) {
+		if !seen[t.term] {
+			seen[t.term] = true
+			terms = append(terms, t.term)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/middleware"
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+const (
+	apiKeyPrefix = "pk"
+	// lastUsedResolution limits how often last_used_at is written for a key.
+	lastUsedResolution = time.Minute
+)
+
+var ErrInvalidScope = errors.New("invalid scope")
+
+// APIKeyService manages API keys for machine clients. Keys have the form
+// pk_<prefix>_<secret>; the prefix identifies the key and the whole key is
+// stored hashed.
+type APIKeyService struct {
+	repo store.APIKeyRepository
+}
+
+func NewAPIKeyService(repo store.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		repo: repo,
+	}
+}
+
+// CreateAPIKey stores a new key and returns it along with the plain key,
+// which cannot be recovered later.
+func (s *APIKeyService) CreateAPIKey(name string, scopes []models.Permission, expiresAt *time.Time, createdBy string) (*models.APIKey, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return nil, "", ErrInvalidScope
+		}
+	}
+
+	prefixBytes := make([]byte, 4)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return nil, "", err
+	}
+	prefix := hex.EncodeToString(prefixBytes)
+
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	plain := apiKeyPrefix + "_" + prefix + "_" + secret
+
+	key := &models.APIKey{
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hashToken(plain),
+		Scopes:    scopes,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.Create(context.Background(), key); err != nil {
+		return nil, "", err
+	}
+
+	return key, plain, nil
+}
+
+func (s *APIKeyService) GetAllAPIKeys() ([]models.APIKey, error) {
+	return s.repo.FindAll(context.Background())
+}
+
+func (s *APIKeyService) RevokeAPIKey(id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+
+	return s.repo.Revoke(context.Background(), objectID, time.Now())
+}
+
+// AuthenticateAPIKey implements middleware.APIKeyAuthenticator.
+func (s *APIKeyService) AuthenticateAPIKey(plain string) (*models.APIKey, error) {
+	parts := strings.SplitN(plain, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix {
+		return nil, middleware.ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.FindByPrefix(context.Background(), parts[1])
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, middleware.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashToken(plain))) != 1 {
+		return nil, middleware.ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		return nil, middleware.ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.repo.UpdateLastUsed(context.Background(), key.ID, now); err != nil {
+			return nil, err
+		}
+		key.LastUsedAt = &now
+	}
+
+	return key, nil
+}
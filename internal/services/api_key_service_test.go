@@ -0,0 +1,36 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/middleware"
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+func TestAPIKeyServiceRevoke(t *testing.T) {
+	service := NewAPIKeyService(store.NewMemoryStore().APIKeys)
+	key, plain, err := service.CreateAPIKey("deploy", []models.Permission{models.PermissionProjectsRead}, nil, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AuthenticateAPIKey(plain); err != nil {
+		t.Fatalf("AuthenticateAPIKey: %v", err)
+	}
+
+	if err := service.RevokeAPIKey(key.ID.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AuthenticateAPIKey(plain); !errors.Is(err, middleware.ErrInvalidAPIKey) {
+		t.Errorf("AuthenticateAPIKey after revoking: %v, want ErrInvalidAPIKey", err)
+	}
+
+	for _, id := range []string{"not-an-id", primitive.NewObjectID().Hex()} {
+		if err := service.RevokeAPIKey(id); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("RevokeAPIKey(%q): %v, want store.ErrNotFound", id, err)
+		}
+	}
+}
@@ -2,27 +2,25 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
-	"portfolio-backend/internal/database"
 	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
 )
 
 type ContactService struct {
-	db           *database.MongoDB
-	collection   *mongo.Collection
+	repo         store.ContactRepository
 	emailService *EmailService
 }
 
-func NewContactService(db *database.MongoDB, emailService *EmailService) *ContactService {
+func NewContactService(repo store.ContactRepository, emailService *EmailService) *ContactService {
 	return &ContactService{
-		db:           db,
-		collection:   db.GetCollection("contacts"),
+		repo:         repo,
 		emailService: emailService,
 	}
 }
@@ -30,9 +28,10 @@ func NewContactService(db *database.MongoDB, emailService *EmailService) *Contac
 func (s *ContactService) CreateContact(contact *models.Contact) error {
 	contact.CreatedAt = time.Now()
 	contact.Read = false
+	contact.Version = 1
+	contact.DeletedAt = nil
 
-	_, err := s.collection.InsertOne(context.Background(), contact)
-	if err != nil {
+	if err := s.repo.Create(context.Background(), contact); err != nil {
 		return err
 	}
 
@@ -55,20 +54,130 @@ func (s *ContactService) CreateContact(contact *models.Contact) error {
 	return nil
 }
 
-func (s *ContactService) GetAllContacts() ([]models.ContactResponse, error) {
-	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
-	cursor, err := s.collection.Find(context.Background(), bson.M{}, opts)
+// ContactListParams are the query parameters accepted when listing contacts.
+type ContactListParams struct {
+	Page    int       `form:"page"`
+	Limit   int       `form:"limit"`
+	Cursor  string    `form:"cursor"`
+	Read    *bool     `form:"read"`
+	Since   time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until   time.Time `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+	Email   string    `form:"email"`
+	Subject string    `form:"subject"`
+	Sort    string    `form:"sort"`
+	Order   string    `form:"order"`
+}
+
+// GetContacts lists contacts matching params. With a cursor the listing
+// continues after it; otherwise the page number is used.
+func (s *ContactService) GetContacts(params ContactListParams) ([]models.ContactResponse, *models.Pagination, error) {
+	return s.listContacts(params, false)
+}
+
+// GetTrashedContacts lists the contact trash like GetContacts, most recently
+// deleted first by default.
+func (s *ContactService) GetTrashedContacts(params ContactListParams) ([]models.ContactResponse, *models.Pagination, error) {
+	return s.listContacts(params, true)
+}
+
+func (s *ContactService) listContacts(params ContactListParams, trashed bool) ([]models.ContactResponse, *models.Pagination, error) {
+	sortFields := store.ContactSortFields
+	query := store.ContactQuery{
+		Trashed: trashed,
+		Read:    params.Read,
+		Since:   params.Since,
+		Until:   params.Until,
+		Email:   strings.TrimSpace(params.Email),
+		Subject: strings.TrimSpace(params.Subject),
+		SortBy:  "created_at",
+	}
+	if trashed {
+		sortFields = store.TrashedContactSortFields
+		query.SortBy = "deleted_at"
+	}
+
+	if params.Sort != "" {
+		if !slices.Contains(sortFields, params.Sort) {
+			return nil, nil, fmt.Errorf("%w: unknown sort field %q", ErrInvalidListParams, params.Sort)
+		}
+		query.SortBy = params.Sort
+	}
+	asc, err := parseSortOrder(params.Order, query.SortBy != "created_at" && query.SortBy != "deleted_at")
+	if err != nil {
+		return nil, nil, err
+	}
+	query.SortAsc = asc
+
+	limit, page := normalizePage(params.Limit, params.Page)
+	if params.Cursor != "" {
+		if query.After, err = store.DecodeCursor(params.Cursor); err != nil {
+			return nil, nil, err
+		}
+		page = 0
+	} else if query.Skip, err = pageSkip(limit, page); err != nil {
+		return nil, nil, err
+	}
+
+	ctx := context.Background()
+	total, err := s.repo.Count(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Fetch one extra to tell whether another page follows.
+	query.Limit = int64(limit + 1)
+	contacts, err := s.repo.Find(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := newPagination(total, limit, page, len(contacts) > limit)
+	if pagination.HasMore {
+		contacts = contacts[:limit]
+		last := contacts[len(contacts)-1]
+		pagination.NextCursor = store.EncodeCursor(store.Cursor{
+			Value: store.CursorValue(store.ContactSortValue(&last, query.SortBy)),
+			ID:    last.ID,
+		})
+	}
+
+	responses := make([]models.ContactResponse, 0, len(contacts))
+	for i := range contacts {
+		responses = append(responses, contacts[i].ToResponse())
+	}
+
+	return responses, pagination, nil
+}
+
+// SearchContacts runs a full-text search over name, email, subject and
+// message.
+func (s *ContactService) SearchContacts(params SearchParams) ([]models.ContactSearchResult, error) {
+	terms, err := params.normalize()
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(context.Background())
 
-	var contacts []models.ContactResponse
-	if err = cursor.All(context.Background(), &contacts); err != nil {
+	matches, err := s.repo.Search(context.Background(), params.Query, int64(params.Limit))
+	if err != nil {
 		return nil, err
 	}
 
-	return contacts, nil
+	results := make([]models.ContactSearchResult, 0, len(matches))
+	for i := range matches {
+		contact := &matches[i].Contact
+		results = append(results, models.ContactSearchResult{
+			Contact: contact.ToResponse(),
+			Score:   matches[i].Score,
+			Highlights: highlightFields(terms, map[string]string{
+				"name":    contact.Name,
+				"email":   contact.Email,
+				"subject": contact.Subject,
+				"message": contact.Message,
+			}),
+		})
+	}
+
+	return results, nil
 }
 
 func (s *ContactService) GetContactByID(id string) (*models.ContactResponse, error) {
@@ -77,35 +186,103 @@ func (s *ContactService) GetContactByID(id string) (*models.ContactResponse, err
 		return nil, err
 	}
 
-	var contact models.ContactResponse
-	err = s.collection.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&contact)
+	contact, err := s.repo.FindByID(context.Background(), objectID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &contact, nil
+	response := contact.ToResponse()
+	return &response, nil
 }
 
-func (s *ContactService) MarkAsRead(id string) error {
+// MarkAsRead marks a contact as read and returns it. ifMatch lists the
+// versions the client expects (nil for any).
+func (s *ContactService) MarkAsRead(id string, ifMatch []int64) (*models.ContactResponse, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	contact, err := s.repo.FindByID(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(contact.Version, ifMatch); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.MarkAsRead(ctx, objectID, contact.Version); err != nil {
+		return nil, err
+	}
+	contact.Read = true
+	contact.Version++
+
+	response := contact.ToResponse()
+	return &response, nil
+}
+
+// DeleteContact moves a contact to the trash.
+func (s *ContactService) DeleteContact(id string, ifMatch []int64) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	contact, err := s.repo.FindByID(ctx, objectID)
 	if err != nil {
 		return err
 	}
+	if err := checkVersion(contact.Version, ifMatch); err != nil {
+		return err
+	}
 
-	_, err = s.collection.UpdateOne(
-		context.Background(),
-		bson.M{"_id": objectID},
-		bson.M{"$set": bson.M{"read": true}},
-	)
-	return err
+	return s.repo.Trash(ctx, objectID, contact.Version, time.Now())
 }
 
-func (s *ContactService) DeleteContact(id string) error {
+// RestoreContact takes a contact out of the trash and returns it. ifMatch
+// lists the versions the client expects (nil for any).
+func (s *ContactService) RestoreContact(id string, ifMatch []int64) (*models.ContactResponse, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
+		return nil, store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	contact, err := s.repo.FindTrashedByID(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(contact.Version, ifMatch); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Restore(ctx, objectID, contact.Version); err != nil {
+		return nil, err
+	}
+	contact.DeletedAt = nil
+	contact.Version++
+
+	response := contact.ToResponse()
+	return &response, nil
+}
+
+// PurgeContact permanently deletes a contact from the trash.
+func (s *ContactService) PurgeContact(id string, ifMatch []int64) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	contact, err := s.repo.FindTrashedByID(ctx, objectID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(contact.Version, ifMatch); err != nil {
 		return err
 	}
 
-	_, err = s.collection.DeleteOne(context.Background(), bson.M{"_id": objectID})
-	return err
+	return s.repo.Purge(ctx, objectID, contact.Version)
 }
@@ -4,7 +4,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/smtp"
-	"strings"
+	"time"
 )
 
 type EmailService struct {
@@ -24,17 +24,7 @@ func NewEmailService(host, port, username, password string) *EmailService {
 }
 
 func (s *EmailService) SendContactNotification(contactName, contactEmail, subject, message string) error {
-	if s.username == "" || s.password == "" {
-		// Skip email sending if credentials are not configured
-		return nil
-	}
-
-	to := []string{s.username}
-	msg := fmt.Sprintf(`From: %s
-To: %s
-Subject: New Contact Form Submission: %s
-
-Name: %s
+	body := fmt.Sprintf(`Name: %s
 Email: %s
 Subject: %s
 
@@ -43,14 +33,46 @@ Message:
 
 ---
 This message was sent from your portfolio contact form.`,
-		s.username,
-		strings.Join(to, ","),
-		subject,
 		contactName,
 		contactEmail,
 		subject,
 		message)
 
+	return s.send(s.username, "New Contact Form Submission: "+subject, body)
+}
+
+func (s *EmailService) SendPasswordReset(to, displayName, resetLink string, expiry time.Duration) error {
+	body := fmt.Sprintf(`Hi %s,
+
+A password reset was requested for your portfolio admin account.
+Use the link below to choose a new password. It expires in %s and can only be used once.
+
+%s
+
+If you did not request this, you can ignore this email; your password will not change.`,
+		displayName,
+		expiry,
+		resetLink)
+
+	return s.send(to, "Reset your portfolio admin password", body)
+}
+
+func (s *EmailService) send(to, subject, body string) error {
+	if s.username == "" || s.password == "" {
+		// Skip email sending if credentials are not configured
+		return nil
+	}
+
+	msg := fmt.Sprintf(`From: %s
+To: %s
+Subject: %s
+
+%s`,
+		s.username,
+		to,
+		subject,
+		body)
+
 	auth := smtp.PlainAuth("", s.username, s.password, s.host)
 
 	addr := fmt.Sprintf("%s:%s", s.host, s.port)
@@ -84,7 +106,7 @@ This message was sent from your portfolio contact form.`,
 		return fmt.Errorf("failed to set sender: %v", err)
 	}
 
-	if err = client.Rcpt(s.username); err != nil {
+	if err = client.Rcpt(to); err != nil {
 		return fmt.Errorf("failed to set recipient: %v", err)
 	}
 
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+type LoginAuditService struct {
+	repo store.LoginEventRepository
+}
+
+func NewLoginAuditService(repo store.LoginEventRepository) *LoginAuditService {
+	return &LoginAuditService{
+		repo: repo,
+	}
+}
+
+// Record stores a login attempt. Failures to write the audit trail are logged
+// rather than failing the login request.
+func (s *LoginAuditService) Record(username, ip, userAgent string, success bool, reason string) {
+	event := &models.LoginEvent{
+		Username:  normalizeUsername(username),
+		IP:        ip,
+		UserAgent: userAgent,
+		Success:   success,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Create(context.Background(), event); err != nil {
+		log.Println("Failed to record login event:", err)
+	}
+}
+
+func (s *LoginAuditService) GetLoginEvents(filter store.LoginEventFilter) ([]models.LoginEvent, error) {
+	filter.Username = normalizeUsername(filter.Username)
+	return s.repo.Find(context.Background(), filter)
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// Failed attempts allowed before progressive delays kick in.
+	loginFreeAttempts = 2
+	loginMaxDelay     = 30 * time.Second
+	// Tracked entries are swept once the map grows past this size.
+	loginGuardSweepSize = 10000
+)
+
+type loginAttempts struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// LoginGuard tracks failed logins per username and per client IP in memory.
+// After a few failures each further attempt for a username must wait an
+// exponentially growing delay, and reaching the threshold locks the username
+// (or IP) out for the lockout duration.
+type LoginGuard struct {
+	entries       map[string]*loginAttempts
+	mutex         sync.Mutex
+	maxAttempts   int
+	ipMaxAttempts int
+	lockout       time.Duration
+	now           func() time.Time
+}
+
+func NewLoginGuard(maxAttempts, ipMaxAttempts int, lockout time.Duration) *LoginGuard {
+	return &LoginGuard{
+		entries:       make(map[string]*loginAttempts),
+		maxAttempts:   maxAttempts,
+		ipMaxAttempts: ipMaxAttempts,
+		lockout:       lockout,
+		now:           time.Now,
+	}
+}
+
+// Check reports whether a login attempt may proceed and, if not, how long the
+// client has to wait.
+func (g *LoginGuard) Check(username, ip string) (time.Duration, bool) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	now := g.now()
+	var wait time.Duration
+
+	if entry := g.entry("ip:"+ip, now); entry != nil && entry.lockedUntil.After(now) {
+		wait = entry.lockedUntil.Sub(now)
+	}
+
+	if entry := g.entry("user:"+normalizeUsername(username), now); entry != nil {
+		if entry.lockedUntil.After(now) {
+			wait = max(wait, entry.lockedUntil.Sub(now))
+		} else if next := entry.lastFailure.Add(loginDelay(entry.failures)); next.After(now) {
+			wait = max(wait, next.Sub(now))
+		}
+	}
+
+	return wait, wait == 0
+}
+
+// RecordFailure counts a failed attempt against both the username and the IP.
+func (g *LoginGuard) RecordFailure(username, ip string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	now := g.now()
+	if len(g.entries) > loginGuardSweepSize {
+		g.sweep(now)
+	}
+
+	g.fail("user:"+normalizeUsername(username), g.maxAttempts, now)
+	g.fail("ip:"+ip, g.ipMaxAttempts, now)
+}
+
+// RecordSuccess clears the failure count for the username. The IP count is
+// left alone so one valid account cannot be used to reset it.
+func (g *LoginGuard) RecordSuccess(username string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	delete(g.entries, "user:"+normalizeUsername(username))
+}
+
+func (g *LoginGuard) fail(key string, threshold int, now time.Time) {
+	entry := g.entry(key, now)
+	if entry == nil {
+		entry = &loginAttempts{}
+		g.entries[key] = entry
+	}
+
+	entry.failures++
+	entry.lastFailure = now
+	if entry.failures >= threshold {
+		entry.lockedUntil = now.Add(g.lockout)
+	}
+}
+
+// entry returns the tracked attempts for key, dropping it first if it has
+// gone quiet for longer than the lockout duration.
+func (g *LoginGuard) entry(key string, now time.Time) *loginAttempts {
+	entry, ok := g.entries[key]
+	if !ok {
+		return nil
+	}
+	if g.expired(entry, now) {
+		delete(g.entries, key)
+		return nil
+	}
+	return entry
+}
+
+func (g *LoginGuard) expired(entry *loginAttempts, now time.Time) bool {
+	return !entry.lockedUntil.After(now) && now.Sub(entry.lastFailure) > g.lockout
+}
+
+func (g *LoginGuard) sweep(now time.Time) {
+	for key, entry := range g.entries {
+		if g.expired(entry, now) {
+			delete(g.entries, key)
+		}
+	}
+}
+
+// loginDelay is the wait required after the given number of failures:
+// nothing for the first few, then 1s, 2s, 4s, ... up to loginMaxDelay.
+func loginDelay(failures int) time.Duration {
+	if failures <= loginFreeAttempts {
+		return 0
+	}
+	shift := failures - loginFreeAttempts - 1
+	if shift > 10 {
+		return loginMaxDelay
+	}
+	return min(time.Second<<shift, loginMaxDelay)
+}
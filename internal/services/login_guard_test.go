@@ -0,0 +1,126 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestLoginGuard returns a guard with its clock pinned to *now.
+func newTestLoginGuard(now *time.Time) *LoginGuard {
+	guard := NewLoginGuard(5, 20, 15*time.Minute)
+	guard.now = func() time.Time { return *now }
+	return guard
+}
+
+func TestLoginDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{loginFreeAttempts, 0},
+		{loginFreeAttempts + 1, time.Second},
+		{loginFreeAttempts + 2, 2 * time.Second},
+		{loginFreeAttempts + 3, 4 * time.Second},
+		{loginFreeAttempts + 6, loginMaxDelay},
+		{1000, loginMaxDelay},
+	}
+	for _, tt := range tests {
+		if got := loginDelay(tt.failures); got != tt.want {
+			t.Errorf("loginDelay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestLoginGuardDelaysGrow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	guard := newTestLoginGuard(&now)
+
+	for i := 0; i < loginFreeAttempts; i++ {
+		guard.RecordFailure("admin", "10.0.0.1")
+		if wait, ok := guard.Check("admin", "10.0.0.1"); !ok {
+			t.Fatalf("after %d failures: throttled for %v", i+1, wait)
+		}
+	}
+
+	for _, want := range []time.Duration{time.Second, 2 * time.Second} {
+		guard.RecordFailure("admin", "10.0.0.1")
+		if wait, ok := guard.Check("admin", "10.0.0.1"); ok || wait != want {
+			t.Fatalf("Check = %v, %v; want %v", wait, ok, want)
+		}
+		now = now.Add(want)
+		if wait, ok := guard.Check("admin", "10.0.0.1"); !ok {
+			t.Fatalf("still throttled for %v after waiting %v", wait, want)
+		}
+	}
+
+	// Other usernames are not delayed.
+	if wait, ok := guard.Check("editor", "10.0.0.1"); !ok {
+		t.Errorf("another username is throttled for %v", wait)
+	}
+}
+
+func TestLoginGuardLocksOutAtThreshold(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	guard := newTestLoginGuard(&now)
+
+	for i := 0; i < 5; i++ {
+		guard.RecordFailure("Admin", "10.0.0.1")
+	}
+	if wait, ok := guard.Check("admin", "10.0.0.2"); ok || wait != 15*time.Minute {
+		t.Fatalf("Check = %v, %v; want locked out for 15m", wait, ok)
+	}
+
+	now = now.Add(15*time.Minute - time.Second)
+	if wait, ok := guard.Check("admin", "10.0.0.2"); ok || wait != time.Second {
+		t.Fatalf("Check = %v, %v; want 1s left", wait, ok)
+	}
+
+	// Once the lockout expires the username starts over.
+	now = now.Add(time.Second)
+	if wait, ok := guard.Check("admin", "10.0.0.2"); !ok {
+		t.Fatalf("still locked out for %v after expiry", wait)
+	}
+	now = now.Add(time.Second)
+	guard.RecordFailure("admin", "10.0.0.2")
+	if wait, ok := guard.Check("admin", "10.0.0.2"); !ok {
+		t.Errorf("first failure after expiry throttled for %v", wait)
+	}
+}
+
+func TestLoginGuardLocksOutIP(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	guard := newTestLoginGuard(&now)
+
+	// Spread failures over usernames so none of them is locked.
+	for i := 0; i < 20; i++ {
+		guard.RecordFailure(string(rune('a'+i)), "10.0.0.1")
+	}
+	if wait, ok := guard.Check("someone", "10.0.0.1"); ok || wait != 15*time.Minute {
+		t.Fatalf("Check = %v, %v; want the IP locked out for 15m", wait, ok)
+	}
+	if wait, ok := guard.Check("someone", "10.0.0.2"); !ok {
+		t.Errorf("another IP is throttled for %v", wait)
+	}
+}
+
+func TestLoginGuardRecordSuccess(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	guard := newTestLoginGuard(&now)
+
+	for i := 0; i < 19; i++ {
+		guard.RecordFailure("admin", "10.0.0.1")
+		now = now.Add(loginMaxDelay)
+	}
+	guard.RecordSuccess("admin")
+
+	if wait, ok := guard.Check("admin", "10.0.0.1"); !ok {
+		t.Fatalf("throttled for %v after a successful login", wait)
+	}
+
+	// The IP count survives the success, so one more failure locks the IP.
+	guard.RecordFailure("admin", "10.0.0.1")
+	if wait, ok := guard.Check("other", "10.0.0.1"); ok || wait != 15*time.Minute {
+		t.Errorf("Check = %v, %v; want the IP locked out for 15m", wait, ok)
+	}
+}
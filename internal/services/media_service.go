@@ -0,0 +1,371 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // decoders for image.DecodeConfig
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	_ "golang.org/x/image/webp"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/storage"
+	"portfolio-backend/internal/store"
+)
+
+const (
+	maxAltLength      = 300
+	maxFilenameLength = 255
+	// maxMediaPixels bounds the dimensions of uploads, which would otherwise
+	// let a small file decode to a huge image. Decoded, an image at the limit
+	// takes 64 MB.
+	maxMediaPixels = 16_000_000
+	// maxGalleryMedia bounds the gallery of a project.
+	maxGalleryMedia = 50
+)
+
+// mediaTypes maps the content types accepted for upload, as sniffed by
+// http.DetectContentType, to the file extensions they are stored with. SVG
+// is left out on purpose: it can carry scripts.
+var mediaTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+var (
+	// ErrMediaTooLarge is returned for uploads over the size or pixel limit.
+	ErrMediaTooLarge = errors.New("media too large")
+	// ErrUnsupportedMedia is returned for uploads that are not a valid image
+	// of a supported type.
+	ErrUnsupportedMedia = errors.New("unsupported media type")
+	// ErrMediaInUse is returned when deleting media a project or post still
+	// shows.
+	ErrMediaInUse = errors.New("media is in use")
+)
+
+type MediaService struct {
+	repo      store.MediaRepository
+	projects  store.ProjectRepository
+	posts     store.PostRepository
+	storage   storage.Storage
+	maxSize   int64
+	publicURL string
+}
+
+// NewMediaService returns a media service keeping files in storage. Uploads
+// are limited to maxSize bytes. Files are linked under publicURL when it is
+// set, for storage served directly or through a CDN, and through the API
+// otherwise.
+func NewMediaService(repo store.MediaRepository, projects store.ProjectRepository, posts store.PostRepository, storage storage.Storage, maxSize int64, publicURL string) *MediaService {
+	return &MediaService{
+		repo:      repo,
+		projects:  projects,
+		posts:     posts,
+		storage:   storage,
+		maxSize:   maxSize,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// MaxSize returns the largest accepted upload in bytes.
+func (s *MediaService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload stores an image read from r with its resized variants. Its type is
+// sniffed from the content; the filename given by the client is only kept
+// as metadata.
+func (s *MediaService) Upload(r io.Reader, filename, alt, author string) (*models.Media, error) {
+	if utf8.RuneCountInString(alt) > maxAltLength {
+		return nil, &ValidationError{Fields: map[string]string{
+			"alt": fmt.Sprintf("must be at most %d characters", maxAltLength),
+		}}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrMediaTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := mediaTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMedia, contentType)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedMedia, err)
+	}
+	if config.Width*config.Height > maxMediaPixels {
+		return nil, ErrMediaTooLarge
+	}
+
+	sum := sha256.Sum256(data)
+	now := time.Now()
+	media := &models.Media{
+		ID:          primitive.NewObjectID(),
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       config.Width,
+		Height:      config.Height,
+		SHA256:      hex.EncodeToString(sum[:]),
+		Alt:         alt,
+		UploadedBy:  author,
+		CreatedAt:   now,
+	}
+	media.Key = now.UTC().Format("2006/01/") + media.ID.Hex() + ext
+
+	ctx := context.Background()
+	if err := s.generateVariants(ctx, media, data); err != nil {
+		return nil, err
+	}
+	if err := s.storage.Put(ctx, media.Key, bytes.NewReader(data), media.Size, contentType); err != nil {
+		s.deleteFiles(ctx, media.Variants)
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, media); err != nil {
+		s.deleteFiles(ctx, s.files(media))
+		return nil, err
+	}
+
+	s.setURL(media)
+	return media, nil
+}
+
+// MediaListParams are the query parameters accepted when listing media.
+type MediaListParams struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+// GetMedia lists the media library, newest first.
+func (s *MediaService) GetMedia(params MediaListParams) ([]models.Media, *models.Pagination, error) {
+	limit, page := normalizePage(params.Limit, params.Page)
+	skip, err := pageSkip(limit, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := context.Background()
+	media, err := s.repo.Find(ctx, store.MediaQuery{
+		Skip:  skip,
+		Limit: int64(limit),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	total, err := s.repo.Count(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range media {
+		s.setURL(&media[i])
+	}
+	if media == nil {
+		media = []models.Media{}
+	}
+	return media, newPagination(total, limit, page, int64(page*limit) < total), nil
+}
+
+// GetMediaByID returns media metadata. An id that is not a valid ObjectID
+// is reported as store.ErrNotFound.
+func (s *MediaService) GetMediaByID(id string) (*models.Media, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+
+	media, err := s.repo.FindByID(context.Background(), objectID)
+	if err != nil {
+		return nil, err
+	}
+	s.setURL(media)
+	return media, nil
+}
+
+// OpenMedia returns media metadata with the file of one of its variants, or
+// of the original for models.MediaVariantOriginal. The caller must close the
+// file.
+func (s *MediaService) OpenMedia(id, variantName string) (*models.Media, *models.MediaVariant, io.ReadCloser, error) {
+	media, err := s.GetMediaByID(id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	v, err := variant(media, variantName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	file, err := s.storage.Open(context.Background(), v.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return media, v, file, nil
+}
+
+// DeleteMedia removes media that no project, including a trashed one, has
+// in its gallery and no post has as its cover.
+func (s *MediaService) DeleteMedia(id string) error {
+	media, err := s.GetMediaByID(id)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	inUse, err := s.projects.UsesMedia(ctx, media.ID)
+	if err != nil {
+		return err
+	}
+	if !inUse {
+		if inUse, err = s.posts.UsesMedia(ctx, media.ID); err != nil {
+			return err
+		}
+	}
+	if inUse {
+		return ErrMediaInUse
+	}
+
+	if err := s.repo.Delete(ctx, media.ID); err != nil {
+		return err
+	}
+	s.deleteFiles(ctx, s.files(media))
+	return nil
+}
+
+// checkGallery validates the media ids of a project gallery.
+func (s *MediaService) checkGallery(ctx context.Context, ids []primitive.ObjectID) error {
+	invalid := func(reason string) error {
+		return &ValidationError{Fields: map[string]string{"media_ids": reason}}
+	}
+	if len(ids) > maxGalleryMedia {
+		return invalid(fmt.Sprintf("must list at most %d media", maxGalleryMedia))
+	}
+
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return invalid(fmt.Sprintf("contains %q more than once", id.Hex()))
+		}
+		seen[id] = true
+	}
+
+	found, err := s.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if len(found) < len(ids) {
+		for _, media := range found {
+			delete(seen, media.ID)
+		}
+		for _, id := range ids {
+			if seen[id] {
+				return invalid(fmt.Sprintf("no media has id %q", id.Hex()))
+			}
+		}
+	}
+	return nil
+}
+
+// checkCover validates the cover media id of a post, which may be unset.
+func (s *MediaService) checkCover(ctx context.Context, id *primitive.ObjectID) error {
+	if id == nil {
+		return nil
+	}
+	_, err := s.repo.FindByID(ctx, *id)
+	if errors.Is(err, store.ErrNotFound) {
+		return &ValidationError{Fields: map[string]string{
+			"cover_media_id": fmt.Sprintf("no media has id %q", id.Hex()),
+		}}
+	}
+	return err
+}
+
+// galleries resolves the media ids of several projects with one query. Ids
+// of deleted media, which old revisions may still hold, are skipped.
+func (s *MediaService) galleries(ctx context.Context, ids [][]primitive.ObjectID) ([][]models.MediaResponse, error) {
+	var all []primitive.ObjectID
+	for _, list := range ids {
+		all = append(all, list...)
+	}
+	found, err := s.repo.FindByIDs(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]models.MediaResponse, len(found))
+	for i := range found {
+		s.setURL(&found[i])
+		byID[found[i].ID] = found[i].ToResponse()
+	}
+
+	galleries := make([][]models.MediaResponse, len(ids))
+	for i, list := range ids {
+		galleries[i] = make([]models.MediaResponse, 0, len(list))
+		for _, id := range list {
+			if media, ok := byID[id]; ok {
+				galleries[i] = append(galleries[i], media)
+			}
+		}
+	}
+	return galleries, nil
+}
+
+// files lists every stored file of media: its variants and the original.
+func (s *MediaService) files(media *models.Media) []models.MediaVariant {
+	original, _ := variant(media, models.MediaVariantOriginal)
+	return append(slices.Clone(media.Variants), *original)
+}
+
+// setURL fills in the URLs of media and its variants.
+func (s *MediaService) setURL(media *models.Media) {
+	if s.publicURL != "" {
+		media.URL = s.publicURL + "/" + media.Key
+		for i := range media.Variants {
+			media.Variants[i].URL = s.publicURL + "/" + media.Variants[i].Key
+		}
+		return
+	}
+
+	base := "/api/v1/media/" + media.ID.Hex()
+	media.URL = base + "/file"
+	for i := range media.Variants {
+		media.Variants[i].URL = base + "/variants/" + media.Variants[i].Name
+	}
+}
+
+// cleanFilename keeps the base name of a client-supplied filename, cut to
+// maxFilenameLength characters.
+func cleanFilename(filename string) string {
+	name := path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	if utf8.RuneCountInString(name) > maxFilenameLength {
+		name = string([]rune(name)[:maxFilenameLength])
+	}
+	return name
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"path"
+	"runtime"
+	"strings"
+
+	"portfolio-backend/internal/imaging"
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+// mediaVariants are the variants generated for uploads, narrowest first.
+// Images no wider than a variant get no copy of that size.
+var mediaVariants = []struct {
+	name  string
+	width int
+}{
+	{models.MediaVariantThumbnail, 320},
+	{models.MediaVariantMedium, 768},
+	{models.MediaVariantLarge, 1600},
+}
+
+// decodeSlots bounds how many images are decoded and resized at once, so
+// concurrent uploads cannot exhaust memory.
+var decodeSlots = make(chan struct{}, runtime.GOMAXPROCS(0))
+
+// generateVariants decodes the image in data, stores its resized variants
+// next to the original and sets them and its placeholders on media. Animated
+// GIFs are resized from their first frame.
+func (s *MediaService) generateVariants(ctx context.Context, media *models.Media, data []byte) error {
+	decodeSlots <- struct{}{}
+	defer func() { <-decodeSlots }()
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedMedia, err)
+	}
+	media.Blurhash, media.DominantColor = imaging.Placeholder(img)
+
+	base := strings.TrimSuffix(media.Key, path.Ext(media.Key))
+	variants := make([]models.MediaVariant, 0, len(mediaVariants))
+	for _, spec := range mediaVariants {
+		if spec.width >= media.Width {
+			break
+		}
+
+		resized := imaging.Resize(img, spec.width)
+		encoded, contentType, ext, err := imaging.Encode(resized)
+		if err != nil {
+			s.deleteFiles(ctx, variants)
+			return err
+		}
+		sum := sha256.Sum256(encoded)
+		variant := models.MediaVariant{
+			Name:        spec.name,
+			Key:         base + "-" + spec.name + ext,
+			ContentType: contentType,
+			Size:        int64(len(encoded)),
+			Width:       resized.Bounds().Dx(),
+			Height:      resized.Bounds().Dy(),
+			SHA256:      hex.EncodeToString(sum[:]),
+		}
+		if err := s.storage.Put(ctx, variant.Key, bytes.NewReader(encoded), variant.Size, contentType); err != nil {
+			s.deleteFiles(ctx, variants)
+			return err
+		}
+		variants = append(variants, variant)
+	}
+
+	media.Variants = variants
+	return nil
+}
+
+// GenerateMissingVariants generates the variants and placeholders of media
+// uploaded before they existed, and returns how many were updated. Media
+// whose original cannot be read or decoded is logged and skipped; the
+// returned error then lists it.
+func (s *MediaService) GenerateMissingVariants(ctx context.Context) (int, error) {
+	updated := 0
+	var failed []string
+	for skip := int64(0); ; skip += maxPageLimit {
+		page, err := s.repo.Find(ctx, store.MediaQuery{Skip: skip, Limit: maxPageLimit})
+		if err != nil {
+			return updated, err
+		}
+
+		for i := range page {
+			media := &page[i]
+			if media.Blurhash != "" {
+				continue
+			}
+			if err := s.regenerate(ctx, media); err != nil {
+				log.Printf("Failed to generate variants for media %s: %v", media.ID.Hex(), err)
+				failed = append(failed, media.ID.Hex())
+				continue
+			}
+			updated++
+		}
+		if len(page) < maxPageLimit {
+			break
+		}
+	}
+
+	if len(failed) > 0 {
+		return updated, fmt.Errorf("failed to generate variants for %d media: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return updated, nil
+}
+
+func (s *MediaService) regenerate(ctx context.Context, media *models.Media) error {
+	file, err := s.storage.Open(ctx, media.Key)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	if err := s.generateVariants(ctx, media, data); err != nil {
+		return err
+	}
+	if err := s.repo.Update(ctx, media); err != nil {
+		s.deleteFiles(ctx, media.Variants)
+		return err
+	}
+	return nil
+}
+
+// variant returns the named variant of media; MediaVariantOriginal is the
+// upload itself.
+func variant(media *models.Media, name string) (*models.MediaVariant, error) {
+	if name == models.MediaVariantOriginal {
+		return &models.MediaVariant{
+			Name:        name,
+			Key:         media.Key,
+			ContentType: media.ContentType,
+			Size:        media.Size,
+			Width:       media.Width,
+			Height:      media.Height,
+			SHA256:      media.SHA256,
+		}, nil
+	}
+	for i := range media.Variants {
+		if media.Variants[i].Name == name {
+			return &media.Variants[i], nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+// deleteFiles removes the files of variants, logging failures: a file left
+// behind is only wasted space.
+func (s *MediaService) deleteFiles(ctx context.Context, variants []models.MediaVariant) {
+	for _, v := range variants {
+		if err := s.storage.Delete(ctx, v.Key); err != nil {
+			log.Printf("Failed to delete media file %s: %v", v.Key, err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	ErrInvalidMFACode       = errors.New("invalid two-factor code")
+	ErrMFAAlreadyEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled        = errors.New("two-factor authentication is not enabled")
+	ErrMFAEnrollmentMissing = errors.New("no pending two-factor enrollment")
+)
+
+type TOTPEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFAService manages TOTP enrollment and verification for users.
+type MFAService struct {
+	users  store.UserRepository
+	issuer string
+	// now is the clock used for TOTP checks; tests can replace it.
+	now func() time.Time
+}
+
+func NewMFAService(users store.UserRepository, issuer string) *MFAService {
+	return &MFAService{
+		users:  users,
+		issuer: issuer,
+		now:    time.Now,
+	}
+}
+
+// BeginEnrollment generates a new pending TOTP secret for the user. It only
+// becomes active once confirmed with ConfirmEnrollment.
+func (s *MFAService) BeginEnrollment(username string) (*TOTPEnrollment, error) {
+	user, err := s.users.FindByUsername(context.Background(), username)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPPendingSecret = secret
+	if err := s.users.Update(context.Background(), user); err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: TOTPProvisioningURI(s.issuer, user.Username, secret),
+	}, nil
+}
+
+// ConfirmEnrollment activates the pending secret if code is valid for it and
+// returns a fresh set of recovery codes. The codes are only shown once.
+func (s *MFAService) ConfirmEnrollment(username, code string) ([]string, error) {
+	user, err := s.users.FindByUsername(context.Background(), username)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPPendingSecret == "" {
+		return nil, ErrMFAEnrollmentMissing
+	}
+
+	step, ok := ValidateTOTP(user.TOTPPendingSecret, code, s.now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPEnabled = true
+	user.TOTPSecret = user.TOTPPendingSecret
+	user.TOTPPendingSecret = ""
+	user.TOTPLastStep = step
+	user.RecoveryCodes = hashes
+	if err := s.users.Update(context.Background(), user); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable turns two-factor authentication off after verifying a current code
+// or recovery code.
+func (s *MFAService) Disable(username, code string) error {
+	user, err := s.users.FindByUsername(context.Background(), username)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return ErrMFANotEnabled
+	}
+	if err := s.Verify(user, code); err != nil {
+		return err
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	user.RecoveryCodes = nil
+	return s.users.Update(context.Background(), user)
+}
+
+// Verify accepts either a TOTP code or an unused recovery code for the user.
+// Each TOTP step and each recovery code can only be used once.
+func (s *MFAService) Verify(user *models.User, code string) error {
+	if !user.TOTPEnabled {
+		return ErrMFANotEnabled
+	}
+
+	if step, ok := ValidateTOTP(user.TOTPSecret, code, s.now()); ok {
+		if step <= user.TOTPLastStep {
+			return ErrInvalidMFACode
+		}
+		user.TOTPLastStep = step
+		return s.users.Update(context.Background(), user)
+	}
+
+	hash := hashToken(normalizeRecoveryCode(code))
+	for i, stored := range user.RecoveryCodes {
+		if stored == hash {
+			user.RecoveryCodes = append(user.RecoveryCodes[:i:i], user.RecoveryCodes[i+1:]...)
+			return s.users.Update(context.Background(), user)
+		}
+	}
+
+	return ErrInvalidMFACode
+}
+
+// generateRecoveryCodes returns the plain codes for the user and the hashes
+// to store.
+func generateRecoveryCodes() ([]string, []string, error) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz234567"
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		for j := range b {
+			b[j] = alphabet[b[j]&31]
+		}
+		codes[i] = string(b[:5]) + "-" + string(b[5:])
+		hashes[i] = hashToken(normalizeRecoveryCode(codes[i]))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
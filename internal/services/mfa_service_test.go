@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+// newTestMFAService returns an MFA service over a memory store holding the
+// user "admin", with its clock pinned to *now.
+func newTestMFAService(t *testing.T, now *time.Time) (*MFAService, store.UserRepository) {
+	t.Helper()
+	users := store.NewMemoryStore().Users
+	if err := users.Create(context.Background(), &models.User{Username: "admin", Role: models.RoleOwner}); err != nil {
+		t.Fatal(err)
+	}
+	service := NewMFAService(users, "Portfolio")
+	service.now = func() time.Time { return *now }
+	return service, users
+}
+
+// enroll enables two-factor authentication for "admin" at now and returns
+// the secret and recovery codes.
+func enroll(t *testing.T, service *MFAService, now time.Time) (string, []string) {
+	t.Helper()
+	enrollment, err := service.BeginEnrollment("admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, err := service.ConfirmEnrollment("admin", totpCode(t, enrollment.Secret, now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enrollment.Secret, codes
+}
+
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	code, err := TOTPCode(secret, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func findAdmin(t *testing.T, users store.UserRepository) *models.User {
+	t.Helper()
+	user, err := users.FindByUsername(context.Background(), "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func TestMFAServiceEnrollment(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	service, users := newTestMFAService(t, &now)
+
+	enrollment, err := service.BeginEnrollment("admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/Portfolio:admin?") {
+		t.Errorf("ProvisioningURI = %s", enrollment.ProvisioningURI)
+	}
+	if user := findAdmin(t, users); user.TOTPEnabled || user.TOTPPendingSecret != enrollment.Secret {
+		t.Fatal("enrollment is not pending")
+	}
+
+	wrong := totpCode(t, enrollment.Secret, now.Add(5*time.Minute))
+	if _, err := service.ConfirmEnrollment("admin", wrong); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("ConfirmEnrollment with a wrong code: %v, want ErrInvalidMFACode", err)
+	}
+
+	codes, err := service.ConfirmEnrollment("admin", totpCode(t, enrollment.Secret, now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Errorf("got %d recovery codes, want %d", len(codes), recoveryCodeCount)
+	}
+
+	user := findAdmin(t, users)
+	if !user.TOTPEnabled || user.TOTPSecret != enrollment.Secret || user.TOTPPendingSecret != "" {
+		t.Error("enrollment was not confirmed")
+	}
+	if user.TOTPLastStep != totpStep(now) {
+		t.Errorf("TOTPLastStep = %d, want %d", user.TOTPLastStep, totpStep(now))
+	}
+	for _, code := range codes {
+		for _, hash := range user.RecoveryCodes {
+			if strings.Contains(hash, normalizeRecoveryCode(code)) {
+				t.Fatal("recovery codes are stored in plain text")
+			}
+		}
+	}
+
+	if _, err := service.BeginEnrollment("admin"); !errors.Is(err, ErrMFAAlreadyEnabled) {
+		t.Errorf("BeginEnrollment when enabled: %v, want ErrMFAAlreadyEnabled", err)
+	}
+}
+
+func TestMFAServiceConfirmWithoutEnrollment(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	service, _ := newTestMFAService(t, &now)
+
+	if _, err := service.ConfirmEnrollment("admin", "123456"); !errors.Is(err, ErrMFAEnrollmentMissing) {
+		t.Errorf("ConfirmEnrollment: %v, want ErrMFAEnrollmentMissing", err)
+	}
+}
+
+func TestMFAServiceRejectsReplayedStep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	service, users := newTestMFAService(t, &now)
+	secret, _ := enroll(t, service, now)
+
+	// The code confirming the enrollment cannot be used to log in.
+	if err := service.Verify(findAdmin(t, users), totpCode(t, secret, now)); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("Verify with the enrollment code: %v, want ErrInvalidMFACode", err)
+	}
+
+	now = now.Add(totpPeriod * time.Second)
+	code := totpCode(t, secret, now)
+	if err := service.Verify(findAdmin(t, users), code); err != nil {
+		t.Fatalf("Verify with the next code: %v", err)
+	}
+	if err := service.Verify(findAdmin(t, users), code); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("Verify with a replayed code: %v, want ErrInvalidMFACode", err)
+	}
+
+	// Nor can the code of an earlier step still inside the skew window.
+	earlier := totpCode(t, secret, now.Add(-totpPeriod*time.Second))
+	if err := service.Verify(findAdmin(t, users), earlier); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("Verify with an earlier code: %v, want ErrInvalidMFACode", err)
+	}
+}
+
+func TestMFAServiceSkewWindow(t *testing.T) {
+	tests := []struct {
+		offset time.Duration
+		ok     bool
+	}{
+		{-2 * totpPeriod * time.Second, false},
+		{-totpPeriod * time.Second, true},
+		{totpPeriod * time.Second, true},
+		{2 * totpPeriod * time.Second, false},
+	}
+	for _, tt := range tests {
+		enrolledAt := time.Unix(1700000000, 0)
+		now := enrolledAt
+		service, users := newTestMFAService(t, &now)
+		secret, _ := enroll(t, service, enrolledAt)
+
+		// Verify a few steps later, so earlier codes are not replays.
+		now = enrolledAt.Add(5 * totpPeriod * time.Second)
+		err := service.Verify(findAdmin(t, users), totpCode(t, secret, now.Add(tt.offset)))
+		if tt.ok && err != nil {
+			t.Errorf("code from %v away: %v", tt.offset, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidMFACode) {
+			t.Errorf("code from %v away: %v, want ErrInvalidMFACode", tt.offset, err)
+		}
+	}
+}
+
+func TestMFAServiceRecoveryCodesAreSingleUse(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	service, users := newTestMFAService(t, &now)
+	_, codes := enroll(t, service, now)
+
+	if err := service.Verify(findAdmin(t, users), codes[0]); err != nil {
+		t.Fatalf("Verify with a recovery code: %v", err)
+	}
+	if err := service.Verify(findAdmin(t, users), codes[0]); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("Verify with a used recovery code: %v, want ErrInvalidMFACode", err)
+	}
+	if n := len(findAdmin(t, users).RecoveryCodes); n != recoveryCodeCount-1 {
+		t.Errorf("%d recovery codes left, want %d", n, recoveryCodeCount-1)
+	}
+
+	// Codes are accepted regardless of case and hyphens.
+	typed := strings.ToUpper(strings.ReplaceAll(codes[1], "-", ""))
+	if err := service.Verify(findAdmin(t, users), typed); err != nil {
+		t.Errorf("Verify with %q: %v", typed, err)
+	}
+}
+
+func TestMFAServiceDisable(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	service, users := newTestMFAService(t, &now)
+	_, codes := enroll(t, service, now)
+
+	if err := service.Disable("admin", "000000"); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("Disable with a wrong code: %v, want ErrInvalidMFACode", err)
+	}
+	if err := service.Disable("admin", codes[0]); err != nil {
+		t.Fatal(err)
+	}
+	user := findAdmin(t, users)
+	if user.TOTPEnabled || user.TOTPSecret != "" || len(user.RecoveryCodes) != 0 {
+		t.Error("two-factor authentication was not disabled")
+	}
+	if err := service.Disable("admin", codes[1]); !errors.Is(err, ErrMFANotEnabled) {
+		t.Errorf("Disable when not enabled: %v, want ErrMFANotEnabled", err)
+	}
+}
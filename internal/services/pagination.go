@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"portfolio-backend/internal/models"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// ErrInvalidListParams is returned for unsupported sort fields or orders.
+var ErrInvalidListParams = errors.New("invalid list parameters")
+
+// normalizePage clamps limit to [1, maxPageLimit] and page to >= 1.
+func normalizePage(limit, page int) (int, int) {
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	if page <= 0 {
+		page = 1
+	}
+	return limit, page
+}
+
+// pageSkip returns the number of items before page of a normalized listing,
+// rejecting pages so far out that the offset would overflow.
+func pageSkip(limit, page int) (int64, error) {
+	if page > math.MaxInt/limit {
+		return 0, fmt.Errorf("%w: page is out of range", ErrInvalidListParams)
+	}
+	return int64((page - 1) * limit), nil
+}
+
+// parseSortOrder reports whether order is ascending. An empty order falls
+// back to defaultAsc.
+func parseSortOrder(order string, defaultAsc bool) (bool, error) {
+	switch strings.ToLower(order) {
+	case "":
+		return defaultAsc, nil
+	case "asc":
+		return true, nil
+	case "desc":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: order must be asc or desc", ErrInvalidListParams)
+	}
+}
+
+// newPagination builds the pagination metadata for a listing. page is zero
+// for cursor-based requests.
+func newPagination(total int64, limit, page int, hasMore bool) *models.Pagination {
+	pagination := &models.Pagination{
+		Total:   total,
+		Limit:   limit,
+		HasMore: hasMore,
+	}
+	if page > 0 {
+		pagination.Page = page
+		pagination.TotalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+	return pagination
+}
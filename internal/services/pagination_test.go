@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"portfolio-backend/internal/store"
+)
+
+func TestPageSkip(t *testing.T) {
+	tests := []struct {
+		limit, page int
+		want        int64
+	}{
+		{20, 1, 0},
+		{20, 3, 40},
+		{100, math.MaxInt / 100, int64((math.MaxInt/100 - 1) * 100)},
+	}
+	for _, tt := range tests {
+		got, err := pageSkip(tt.limit, tt.page)
+		if err != nil || got != tt.want {
+			t.Errorf("pageSkip(%d, %d) = %d, %v; want %d", tt.limit, tt.page, got, err, tt.want)
+		}
+	}
+
+	for _, page := range []int{math.MaxInt/100 + 1, math.MaxInt} {
+		if _, err := pageSkip(100, page); !errors.Is(err, ErrInvalidListParams) {
+			t.Errorf("pageSkip(100, %d): %v, want ErrInvalidListParams", page, err)
+		}
+	}
+}
+
+func TestListingsRejectPagesOutOfRange(t *testing.T) {
+	st := store.NewMemoryStore()
+	const page = 100000000000000000
+
+	contacts := NewContactService(st.Contacts, nil)
+	if _, _, err := contacts.GetContacts(ContactListParams{Page: page, Limit: 100}); !errors.Is(err, ErrInvalidListParams) {
+		t.Errorf("GetContacts: %v, want ErrInvalidListParams", err)
+	}
+
+	// Far but representable pages are empty rather than an error.
+	list, pagination, err := contacts.GetContacts(ContactListParams{Page: page, Limit: 10})
+	if err != nil || len(list) != 0 || pagination.HasMore {
+		t.Errorf("GetContacts far page = %v, %+v, %v", list, pagination, err)
+	}
+}
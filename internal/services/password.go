@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+const minPasswordLength = 12
+
+var ErrWeakPassword = errors.New("password is too weak")
+
+// commonPasswords rejects a few passwords that meet the character rules once
+// capitalised but are still trivially guessable. Compared in lowercase.
+var commonPasswords = map[string]bool{
+	"password1234": true,
+	"password123!": true,
+	"qwerty123456": true,
+	"changeme1234": true,
+	"letmein12345": true,
+	"welcome12345": true,
+}
+
+// PasswordStrengthError explains why a password was rejected.
+type PasswordStrengthError struct {
+	Reason string
+}
+
+func (e *PasswordStrengthError) Error() string {
+	return "password is too weak: " + e.Reason
+}
+
+func (e *PasswordStrengthError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// ValidatePasswordStrength requires at least minPasswordLength characters
+// drawn from three of: lowercase, uppercase, digits and symbols, and rejects
+// passwords containing the username or found in commonPasswords.
+func ValidatePasswordStrength(password, username string) error {
+	if len([]rune(password)) < minPasswordLength {
+		return &PasswordStrengthError{Reason: "must be at least 12 characters"}
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+	if classes < 3 {
+		return &PasswordStrengthError{Reason: "must mix at least three of lowercase, uppercase, digits and symbols"}
+	}
+
+	lowered := strings.ToLower(password)
+	if username != "" && strings.Contains(lowered, normalizeUsername(username)) {
+		return &PasswordStrengthError{Reason: "must not contain the username"}
+	}
+	if commonPasswords[lowered] {
+		return &PasswordStrengthError{Reason: "is too common"}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"time"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired reset token")
+
+// PasswordResetService issues single-use password reset tokens by email and
+// redeems them.
+type PasswordResetService struct {
+	tokens       store.PasswordResetTokenRepository
+	users        store.UserRepository
+	userService  *UserService
+	tokenService *TokenService
+	emailService *EmailService
+	resetURL     string
+	expiry       time.Duration
+}
+
+func NewPasswordResetService(
+	tokens store.PasswordResetTokenRepository,
+	users store.UserRepository,
+	userService *UserService,
+	tokenService *TokenService,
+	emailService *EmailService,
+	resetURL string,
+	expiry time.Duration,
+) *PasswordResetService {
+	return &PasswordResetService{
+		tokens:       tokens,
+		users:        users,
+		userService:  userService,
+		tokenService: tokenService,
+		emailService: emailService,
+		resetURL:     resetURL,
+		expiry:       expiry,
+	}
+}
+
+// RequestReset emails a reset link if email belongs to an active user. It
+// returns nil for unknown addresses so callers cannot probe for accounts.
+func (s *PasswordResetService) RequestReset(email string) error {
+	user, err := s.users.FindByEmail(context.Background(), normalizeEmail(email))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Disabled {
+		return nil
+	}
+
+	plain, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.tokens.Create(context.Background(), &models.PasswordResetToken{
+		TokenHash: hashToken(plain),
+		Username:  user.Username,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.expiry),
+	}); err != nil {
+		return err
+	}
+
+	link, err := url.Parse(s.resetURL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", plain)
+	link.RawQuery = query.Encode()
+
+	go func() {
+		if err := s.emailService.SendPasswordReset(user.Email, user.DisplayName, link.String(), s.expiry); err != nil {
+			log.Println("Failed to send password reset email:", err)
+		}
+	}()
+
+	return nil
+}
+
+// ResetPassword redeems a reset token, sets the new password and revokes all
+// of the user's sessions and outstanding reset tokens.
+func (s *PasswordResetService) ResetPassword(token, newPassword string) error {
+	ctx := context.Background()
+
+	resetToken, err := s.tokens.FindByHash(ctx, hashToken(token))
+	if errors.Is(err, store.ErrNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	if resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.users.FindByUsername(ctx, resetToken.Username)
+	if errors.Is(err, store.ErrNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	// Validate before consuming the token so a weak password can be retried
+	if err := ValidatePasswordStrength(newPassword, user.Username); err != nil {
+		return err
+	}
+
+	used, err := s.tokens.MarkUsed(ctx, resetToken.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+
+	if err := s.userService.SetPassword(user, newPassword); err != nil {
+		return err
+	}
+	if err := s.tokens.InvalidateForUser(ctx, user.Username, time.Now()); err != nil {
+		return err
+	}
+	return s.tokenService.RevokeUserSessions(user.Username)
+}
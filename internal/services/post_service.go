@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/markdown"
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/slug"
+	"portfolio-backend/internal/store"
+)
+
+const (
+	maxPostBodyLength = 100000
+	maxPostTags       = 20
+)
+
+type PostService struct {
+	repo  store.PostRepository
+	media *MediaService
+}
+
+func NewPostService(repo store.PostRepository, media *MediaService) *PostService {
+	return &PostService{
+		repo:  repo,
+		media: media,
+	}
+}
+
+// CreatePost stores a new post written by author. Posts are drafts unless
+// given a published_at, and get a slug derived from the title unless one is
+// given.
+func (s *PostService) CreatePost(post *models.Post, author string) error {
+	if err := validatePost(post); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := s.media.checkCover(ctx, post.CoverMediaID); err != nil {
+		return err
+	}
+
+	post.ID = primitive.NewObjectID()
+	post.Author = author
+	post.CreatedAt = time.Now()
+	post.UpdatedAt = post.CreatedAt
+	post.Version = 1
+
+	if err := s.assignSlug(ctx, post, ""); err != nil {
+		return err
+	}
+	if err := renderBody(post); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, post); err != nil {
+		return slugConflict(err)
+	}
+	return nil
+}
+
+// PostListParams are the query parameters accepted when listing posts.
+type PostListParams struct {
+	Page  int    `form:"page"`
+	Limit int    `form:"limit"`
+	Tag   string `form:"tag"`
+}
+
+// GetPosts lists posts, newest first, optionally with a tag. Unless
+// includeUnpublished is set, only published posts are listed, by
+// publication date; otherwise every post is, by creation date.
+func (s *PostService) GetPosts(params PostListParams, includeUnpublished bool) ([]models.PostResponse, *models.Pagination, error) {
+	limit, page := normalizePage(params.Limit, params.Page)
+	skip, err := pageSkip(limit, page)
+	if err != nil {
+		return nil, nil, err
+	}
+	query := store.PostQuery{
+		Tag:   slug.Make(params.Tag),
+		Skip:  skip,
+		Limit: int64(limit),
+	}
+	if params.Tag != "" && query.Tag == "" {
+		return nil, nil, fmt.Errorf("%w: invalid tag %q", ErrInvalidListParams, params.Tag)
+	}
+	if !includeUnpublished {
+		query.PublicAt = time.Now()
+	}
+
+	ctx := context.Background()
+	total, err := s.repo.Count(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	posts, err := s.repo.Find(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responses, err := s.toResponses(ctx, posts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return responses, newPagination(total, limit, page, int64(page*limit) < total), nil
+}
+
+// GetPostTags returns the tags in use with the number of posts for each,
+// most used first. Unless includeUnpublished is set, only published posts
+// are counted.
+func (s *PostService) GetPostTags(includeUnpublished bool) ([]models.FacetCount, error) {
+	query := store.PostQuery{}
+	if !includeUnpublished {
+		query.PublicAt = time.Now()
+	}
+
+	tags, err := s.repo.Tags(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []models.FacetCount{}
+	}
+	return tags, nil
+}
+
+// GetPostByID returns a post. Unless includeUnpublished is set, posts that
+// are not published yet are reported as store.ErrNotFound, as are ids that
+// are not valid ObjectIDs.
+func (s *PostService) GetPostByID(id string, includeUnpublished bool) (*models.PostResponse, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	post, err := s.repo.FindByID(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if !includeUnpublished && !post.IsPublic(time.Now()) {
+		return nil, store.ErrNotFound
+	}
+	return s.toResponse(ctx, post)
+}
+
+// GetPostBySlug returns the published post with slug.
+func (s *PostService) GetPostBySlug(value string) (*models.PostResponse, error) {
+	ctx := context.Background()
+	post, err := s.repo.FindBySlug(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+	if !post.IsPublic(time.Now()) {
+		return nil, store.ErrNotFound
+	}
+	return s.toResponse(ctx, post)
+}
+
+// UpdatePost replaces the writable fields of a post; an empty slug keeps
+// the current one. ifMatch lists the versions the client expects (nil for
+// any).
+func (s *PostService) UpdatePost(id string, post *models.Post, ifMatch []int64) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	existing, err := s.repo.FindByID(ctx, objectID)
+	if err != nil {
+		return err
+	}
+	if err := validatePost(post); err != nil {
+		return err
+	}
+	if err := s.media.checkCover(ctx, post.CoverMediaID); err != nil {
+		return err
+	}
+	if err := checkVersion(existing.Version, ifMatch); err != nil {
+		return err
+	}
+
+	post.ID = objectID
+	post.Author = existing.Author
+	post.CreatedAt = existing.CreatedAt
+	post.Version = existing.Version
+	post.UpdatedAt = time.Now()
+
+	if err := s.assignSlug(ctx, post, existing.Slug); err != nil {
+		return err
+	}
+	if err := renderBody(post); err != nil {
+		return err
+	}
+	if err := s.repo.Replace(ctx, post); err != nil {
+		return slugConflict(err)
+	}
+	return nil
+}
+
+// DeletePost permanently deletes a post.
+func (s *PostService) DeletePost(id string, ifMatch []int64) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	post, err := s.repo.FindByID(ctx, objectID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(post.Version, ifMatch); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, objectID, post.Version)
+}
+
+// assignSlug settles post.Slug before it is written; current is the stored
+// slug, empty for a new post. Unlike a project's, a post's slug is only
+// derived from the title once, so published links keep working; it changes
+// only when the client asks.
+func (s *PostService) assignSlug(ctx context.Context, post *models.Post, current string) error {
+	switch {
+	case post.Slug == "" && current != "":
+		post.Slug = current
+	case post.Slug == "":
+		generated, err := uniqueSlug(ctx, s.repo, baseSlug(post.Title, post.ID), post.ID)
+		if err != nil {
+			return err
+		}
+		post.Slug = generated
+	case post.Slug != current:
+		taken, err := s.repo.SlugTaken(ctx, post.Slug, post.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errSlugTaken
+		}
+	}
+	return nil
+}
+
+// validatePost checks the writable fields of a post and normalizes its
+// tags to slugs, dropping repeats.
+func validatePost(post *models.Post) error {
+	invalid := make(map[string]string)
+
+	switch {
+	case strings.TrimSpace(post.Title) == "":
+		invalid["title"] = "is required"
+	case utf8.RuneCountInString(post.Title) > maxTitleLength:
+		invalid["title"] = fmt.Sprintf("must be at most %d characters", maxTitleLength)
+	}
+	if post.Slug != "" && !slug.Valid(post.Slug) {
+		invalid["slug"] = fmt.Sprintf("must be lowercase letters and digits separated by single hyphens, at most %d characters", slug.MaxLength)
+	}
+	switch {
+	case strings.TrimSpace(post.Body) == "":
+		invalid["body"] = "is required"
+	case utf8.RuneCountInString(post.Body) > maxPostBodyLength:
+		invalid["body"] = fmt.Sprintf("must be at most %d characters", maxPostBodyLength)
+	}
+
+	tags := make([]string, 0, len(post.Tags))
+	for _, tag := range post.Tags {
+		normalized := slug.Make(tag)
+		switch {
+		case normalized == "":
+			invalid["tags"] = "must only contain values with letters or digits"
+		case !slices.Contains(tags, normalized):
+			tags = append(tags, normalized)
+		}
+	}
+	if len(tags) > maxPostTags {
+		invalid["tags"] = fmt.Sprintf("must list at most %d tags", maxPostTags)
+	}
+	post.Tags = tags
+
+	if len(invalid) > 0 {
+		return &ValidationError{Fields: invalid}
+	}
+	return nil
+}
+
+// renderBody renders the Markdown body of post into the fields derived from
+// it before the post is written.
+func renderBody(post *models.Post) error {
+	doc, err := markdown.Render(post.Body)
+	if err != nil {
+		return err
+	}
+	post.BodyHTML = doc.HTML
+	post.Excerpt = doc.Excerpt
+	post.ReadingTime = doc.ReadingTime
+	return nil
+}
+
+// toResponses converts posts to responses with their cover images.
+func (s *PostService) toResponses(ctx context.Context, posts []models.Post) ([]models.PostResponse, error) {
+	ids := make([][]primitive.ObjectID, len(posts))
+	for i := range posts {
+		if posts[i].CoverMediaID != nil {
+			ids[i] = []primitive.ObjectID{*posts[i].CoverMediaID}
+		}
+	}
+	covers, err := s.media.galleries(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.PostResponse, 0, len(posts))
+	for i := range posts {
+		response := posts[i].ToResponse()
+		if len(covers[i]) > 0 {
+			response.Cover = &covers[i][0]
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (s *PostService) toResponse(ctx context.Context, post *models.Post) (*models.PostResponse, error) {
+	responses, err := s.toResponses(ctx, []models.Post{*post})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
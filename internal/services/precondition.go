@@ -0,0 +1,16 @@
+package services
+
+import (
+	"slices"
+
+	"portfolio-backend/internal/store"
+)
+
+// checkVersion enforces an If-Match precondition. A nil ifMatch means the
+// request had none; otherwise version must be one of the listed versions.
+func checkVersion(version int64, ifMatch []int64) error {
+	if ifMatch != nil && !slices.Contains(ifMatch, version) {
+		return store.ErrVersionConflict
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"portfolio-backend/internal/markdown"
+	"portfolio-backend/internal/models"
+)
+
+// renderDescription renders the Markdown description of project into the
+// fields derived from it before the project is written.
+func renderDescription(project *models.Project) error {
+	doc, err := markdown.Render(project.Description)
+	if err != nil {
+		return err
+	}
+	project.DescriptionHTML = doc.HTML
+	project.Excerpt = doc.Excerpt
+	project.ReadingTime = doc.ReadingTime
+	return nil
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+// maxReorderProjects bounds the number of ids ReorderProjects accepts.
+const maxReorderProjects = 500
+
+// ReorderProjects puts the projects listed in ids into that order and
+// returns them in it; projects not listed keep their positions. Only the
+// listed projects that are out of order move, each to a position between
+// its new neighbours, so moving one project rewrites one document. The
+// moves are applied atomically and recorded as revisions under author.
+func (s *ProjectService) ReorderProjects(ids []string, author string) ([]models.Project, error) {
+	if len(ids) == 0 {
+		return nil, &ValidationError{Fields: map[string]string{"ids": "is required"}}
+	}
+	if len(ids) > maxReorderProjects {
+		return nil, &ValidationError{Fields: map[string]string{
+			"ids": fmt.Sprintf("must list at most %d projects", maxReorderProjects),
+		}}
+	}
+
+	ctx := context.Background()
+	projects := make([]models.Project, len(ids))
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for i, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, &ValidationError{Fields: map[string]string{"ids": fmt.Sprintf("%q is not a valid project id", id)}}
+		}
+		if seen[objectID] {
+			return nil, &ValidationError{Fields: map[string]string{"ids": fmt.Sprintf("contains %q more than once", id)}}
+		}
+		seen[objectID] = true
+
+		project, err := s.repo.FindByID(ctx, objectID)
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, &ValidationError{Fields: map[string]string{"ids": fmt.Sprintf("no project has id %q", id)}}
+		}
+		if err != nil {
+			return nil, err
+		}
+		projects[i] = *project
+	}
+
+	current := make([]float64, len(projects))
+	for i := range projects {
+		current[i] = projects[i].Position
+	}
+	positions := reorderPositions(current)
+
+	now := time.Now()
+	var moved []*models.Project
+	for i := range projects {
+		if positions[i] != current[i] {
+			projects[i].Position = positions[i]
+			projects[i].UpdatedAt = now
+			moved = append(moved, &projects[i])
+		}
+	}
+	if len(moved) == 0 {
+		return projects, nil
+	}
+
+	if err := s.repo.ReplaceMany(ctx, moved); err != nil {
+		return nil, err
+	}
+	for _, project := range moved {
+		if err := s.recordRevision(ctx, project, project.Version, models.RevisionUpdate, author, 0); err != nil {
+			return nil, err
+		}
+	}
+	return projects, nil
+}
+
+// firstPosition returns a position before every project, for new projects
+// that were not given one. Positions below 1 are halved rather than reaching
+// 0, which stands for no position.
+func (s *ProjectService) firstPosition(ctx context.Context) (float64, error) {
+	first, err := s.repo.Find(ctx, store.ProjectQuery{SortBy: "position", SortAsc: true, Limit: 1})
+	if err != nil {
+		return 0, err
+	}
+	if len(first) == 0 {
+		return 1, nil
+	}
+
+	position := first[0].Position
+	switch {
+	case position > 1:
+		return position - 1, nil
+	case position/2 > 0:
+		return position / 2, nil
+	default:
+		// Only positions stored before they had to be positive get here.
+		return position - 1, nil
+	}
+}
+
+// reorderPositions returns strictly increasing positions for projects
+// currently at the given positions. A longest run of projects already in
+// order keeps its positions; the others are spread over the gaps between
+// them, or spaced one apart past either end while that keeps them above 0.
+// Only if the gaps have become too narrow for floating point are all
+// positions renumbered.
+func reorderPositions(current []float64) []float64 {
+	positions := slices.Clone(current)
+	keep := longestIncreasing(current)
+
+	for i := 0; i < len(current); {
+		if keep[i] {
+			i++
+			continue
+		}
+		j := i
+		for j < len(current) && !keep[j] {
+			j++
+		}
+
+		switch {
+		case i > 0 && j < len(current):
+			step := (current[j] - current[i-1]) / float64(j-i+1)
+			for k := i; k < j; k++ {
+				positions[k] = current[i-1] + step*float64(k-i+1)
+			}
+		case i > 0:
+			for k := i; k < j; k++ {
+				positions[k] = current[i-1] + float64(k-i+1)
+			}
+		default:
+			// Before the first kept project, share the gap down to 0 if
+			// spacing one apart would reach it.
+			step := 1.0
+			if current[j] <= float64(j-i) {
+				step = current[j] / float64(j-i+1)
+			}
+			for k := i; k < j; k++ {
+				positions[k] = current[j] - step*float64(j-k)
+			}
+		}
+		i = j
+	}
+
+	for i := 1; i < len(positions); i++ {
+		if positions[i] <= positions[i-1] {
+			first := max(slices.Min(current), 1)
+			for k := range positions {
+				positions[k] = first + float64(k)
+			}
+			break
+		}
+	}
+	return positions
+}
+
+// longestIncreasing marks the elements of a longest strictly increasing
+// subsequence of values.
+func longestIncreasing(values []float64) []bool {
+	keep := make([]bool, len(values))
+	if len(values) == 0 {
+		return keep
+	}
+
+	// tails[k] is the index of the smallest value ending an increasing
+	// subsequence of length k+1; prev links each index to its predecessor.
+	var tails []int
+	prev := make([]int, len(values))
+	for i, v := range values {
+		k := sort.Search(len(tails), func(k int) bool { return values[tails[k]] >= v })
+		prev[i] = -1
+		if k > 0 {
+			prev[i] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+		keep[i] = true
+	}
+	return keep
+}
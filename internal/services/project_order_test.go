@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"portfolio-backend/internal/models"
+)
+
+func TestLongestIncreasing(t *testing.T) {
+	tests := []struct {
+		values []float64
+		want   []bool
+	}{
+		{nil, []bool{}},
+		{[]float64{1}, []bool{true}},
+		{[]float64{1, 2, 3}, []bool{true, true, true}},
+		{[]float64{3, 2, 1}, []bool{false, false, true}},
+		{[]float64{2, 3, 1}, []bool{true, true, false}},
+		{[]float64{5, 1, 2, 3, 4}, []bool{false, true, true, true, true}},
+		{[]float64{1, 5, 2, 3, 4}, []bool{true, false, true, true, true}},
+		{[]float64{1, 1, 2}, []bool{false, true, true}},
+	}
+	for _, tt := range tests {
+		if got := longestIncreasing(tt.values); !slices.Equal(got, tt.want) {
+			t.Errorf("longestIncreasing(%v) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestReorderPositions(t *testing.T) {
+	tests := []struct {
+		current, want []float64
+	}{
+		// Already in order: nothing moves.
+		{[]float64{1, 2, 3}, []float64{1, 2, 3}},
+		// The last project moved first goes before the others.
+		{[]float64{3, 2, 4}, []float64{1, 2, 4}},
+		// ... halfway to 0 when there is no room for a whole step.
+		{[]float64{3, 1, 2}, []float64{0.5, 1, 2}},
+		{[]float64{9, 8, 1.5, 2}, []float64{0.5, 1, 1.5, 2}},
+		// The first project moved last goes after the others.
+		{[]float64{2, 3, 1}, []float64{2, 3, 4}},
+		// A project moved between two others takes the midpoint.
+		{[]float64{1, 4, 2, 3}, []float64{1, 1.5, 2, 3}},
+		// Several moved projects share the gap evenly.
+		{[]float64{0, 9, 8, 3}, []float64{0, 1, 2, 3}},
+		// Positions need not be contiguous.
+		{[]float64{5, 20, 10, 30}, []float64{5, 7.5, 10, 30}},
+		// Positions stored before they had to be positive are renumbered
+		// from 1 when there is no room before them.
+		{[]float64{3, 0, 2}, []float64{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		if got := reorderPositions(tt.current); !slices.Equal(got, tt.want) {
+			t.Errorf("reorderPositions(%v) = %v, want %v", tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestReorderPositionsKeepsOrderedRun(t *testing.T) {
+	current := []float64{7, 1, 9, 2, 3, 8, 4, 0}
+	got := reorderPositions(current)
+
+	assertIncreasing(t, got)
+	keep := longestIncreasing(current)
+	moved := 0
+	for i := range current {
+		if keep[i] && got[i] != current[i] {
+			t.Errorf("position %d of the ordered run changed from %v to %v", i, current[i], got[i])
+		}
+		if got[i] != current[i] {
+			moved++
+		}
+	}
+	if want := len(current) - 4; moved != want {
+		t.Errorf("moved %d projects, want %d", moved, want)
+	}
+}
+
+func TestReorderPositionsRenumbersExhaustedGaps(t *testing.T) {
+	// No float64 fits between the neighbours of the moved project.
+	low := 1.0
+	high := math.Nextafter(low, 2)
+	current := []float64{low, 5, high}
+
+	got := reorderPositions(current)
+	assertIncreasing(t, got)
+	if want := []float64{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("reorderPositions(%v) = %v, want %v", current, got, want)
+	}
+}
+
+func assertIncreasing(t *testing.T, positions []float64) {
+	t.Helper()
+	for i := 1; i < len(positions); i++ {
+		if positions[i] <= positions[i-1] {
+			t.Fatalf("positions %v are not strictly increasing", positions)
+		}
+	}
+}
+
+func TestCreateProjectPositionsStayPositive(t *testing.T) {
+	service, _ := newTestProjectService()
+
+	var positions []float64
+	for i := 0; i < 4; i++ {
+		project := &models.Project{Title: "Project", Description: "A project"}
+		if err := service.CreateProject(project, "admin"); err != nil {
+			t.Fatal(err)
+		}
+		positions = append(positions, project.Position)
+	}
+	if want := []float64{1, 0.5, 0.25, 0.125}; !slices.Equal(positions, want) {
+		t.Errorf("positions = %v, want %v", positions, want)
+	}
+
+	project := &models.Project{Title: "Project", Description: "A project", Position: -1}
+	var validationErr *ValidationError
+	if err := service.CreateProject(project, "admin"); !errors.As(err, &validationErr) || validationErr.Fields["position"] == "" {
+		t.Errorf("CreateProject with a negative position: %v, want a position ValidationError", err)
+	}
+}
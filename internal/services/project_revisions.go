@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+// recordRevision stores a snapshot of project after a write. number is the
+// revision number, which for everything but deletes is the new version.
+func (s *ProjectService) recordRevision(ctx context.Context, project *models.Project, number int64, action, author string, restoredFrom int64) error {
+	return s.revisions.Create(ctx, &models.ProjectRevision{
+		ProjectID:    project.ID,
+		Number:       number,
+		Action:       action,
+		Author:       author,
+		CreatedAt:    time.Now(),
+		RestoredFrom: restoredFrom,
+		Snapshot:     *project,
+	})
+}
+
+// GetRevisions lists the revisions of a project, newest first. Revisions
+// outlive the project, so this also works for deleted projects.
+func (s *ProjectService) GetRevisions(id string) ([]models.ProjectRevision, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	revisions, err := s.revisions.FindByProject(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	// Projects created before history was kept have no revisions yet.
+	if len(revisions) == 0 {
+		if _, err := s.repo.FindByID(ctx, objectID); err != nil {
+			return nil, err
+		}
+	}
+	return revisions, nil
+}
+
+func (s *ProjectService) GetRevision(id string, number int64) (*models.ProjectRevision, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+
+	return s.revisions.FindByNumber(context.Background(), objectID, number)
+}
+
+// DiffRevisions compares the writable fields of two revisions of a project.
+func (s *ProjectService) DiffRevisions(id string, from, to int64) (*models.ProjectRevisionDiff, error) {
+	fromRevision, err := s.GetRevision(id, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.GetRevision(id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fields are compared in their JSON form, as clients see them.
+	before, err := fieldsMap(fromRevision.Snapshot.Fields())
+	if err != nil {
+		return nil, err
+	}
+	after, err := fieldsMap(toRevision.Snapshot.Fields())
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []models.FieldChange{}
+	for _, field := range slices.Sorted(maps.Keys(after)) {
+		if !reflect.DeepEqual(before[field], after[field]) {
+			changes = append(changes, models.FieldChange{
+				Field: field,
+				From:  before[field],
+				To:    after[field],
+			})
+		}
+	}
+
+	return &models.ProjectRevisionDiff{
+		ProjectID: fromRevision.ProjectID,
+		From:      from,
+		To:        to,
+		Changes:   changes,
+	}, nil
+}
+
+// RestoreRevision copies the writable fields of a revision onto the project
+// and records the result as a new revision. A purged project is recreated
+// with its original id; a trashed one must be restored from the trash first.
+// ifMatch lists the versions the client expects (nil for any) and only
+// applies to projects that still exist.
+func (s *ProjectService) RestoreRevision(id string, number int64, ifMatch []int64, author string) (*models.Project, error) {
+	revision, err := s.GetRevision(id, number)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	project, err := s.repo.FindByID(ctx, revision.ProjectID)
+	switch {
+	case err == nil:
+		if err := checkVersion(project.Version, ifMatch); err != nil {
+			return nil, err
+		}
+		existing := *project
+		project.SetFields(revision.Snapshot.Fields())
+		// Revisions from before publication states keep the current status.
+		if project.Status == "" {
+			project.Status = existing.Status
+		}
+		if err := s.validateRestored(ctx, project); err != nil {
+			return nil, err
+		}
+		project.UpdatedAt = time.Now()
+		if err := s.restoreSlug(ctx, project, &existing); err != nil {
+			return nil, err
+		}
+		if err := renderDescription(project); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Replace(ctx, project); err != nil {
+			return nil, slugConflict(err)
+		}
+	case errors.Is(err, store.ErrNotFound):
+		if _, err := s.repo.FindTrashedByID(ctx, revision.ProjectID); err == nil {
+			return nil, ErrTrashed
+		} else if !errors.Is(err, store.ErrNotFound) {
+			return nil, err
+		}
+		if ifMatch != nil {
+			return nil, store.ErrVersionConflict
+		}
+		revisions, err := s.revisions.FindByProject(ctx, revision.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		restored := revision.Snapshot
+		restored.UpdatedAt = time.Now()
+		restored.DeletedAt = nil
+		if restored.Status == "" {
+			restored.Status = models.ProjectStatusDraft
+		}
+		if err := s.validateRestored(ctx, &restored); err != nil {
+			return nil, err
+		}
+		restored.Version = revisions[0].Number + 1
+		if err := s.restoreSlug(ctx, &restored, nil); err != nil {
+			return nil, err
+		}
+		if err := renderDescription(&restored); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Create(ctx, &restored); err != nil {
+			return nil, slugConflict(err)
+		}
+		project = &restored
+	default:
+		return nil, err
+	}
+
+	if err := s.recordRevision(ctx, project, project.Version, models.RevisionRestore, author, number); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// validateRestored applies the checks of a regular write to a restored
+// revision. Revisions recorded under older rules may no longer pass them,
+// and media in the gallery may have been deleted since.
+func (s *ProjectService) validateRestored(ctx context.Context, project *models.Project) error {
+	if err := validateProject(project.Fields()); err != nil {
+		return err
+	}
+	return s.media.checkGallery(ctx, project.MediaIDs)
+}
+
+// restoreSlug is assignSlug for a restored revision. A slug another project
+// has taken since the revision was recorded is not reclaimed; the project
+// keeps its current slug, or gets a new one, instead.
+func (s *ProjectService) restoreSlug(ctx context.Context, project, existing *models.Project) error {
+	taken, err := s.repo.SlugTaken(ctx, project.Slug, project.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		project.Slug = ""
+	}
+	return s.assignSlug(ctx, project, existing)
+}
+
+func fieldsMap(fields models.ProjectFields) (map[string]interface{}, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+)
+
+func TestRestoreRevisionValidatesGallery(t *testing.T) {
+	ctx := context.Background()
+	service, st := newTestProjectService()
+
+	image := &models.Media{Filename: "screenshot.png"}
+	if err := st.Media.Create(ctx, image); err != nil {
+		t.Fatal(err)
+	}
+	project := &models.Project{Title: "Portfolio", Description: "A portfolio", MediaIDs: []primitive.ObjectID{image.ID}}
+	if err := service.CreateProject(project, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	id := project.ID.Hex()
+	if err := service.UpdateProject(id, &models.Project{Title: "Portfolio", Description: "Without a gallery"}, nil, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Media.Delete(ctx, image.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	var validationErr *ValidationError
+	if _, err := service.RestoreRevision(id, 1, nil, "admin"); !errors.As(err, &validationErr) || validationErr.Fields["media_ids"] == "" {
+		t.Fatalf("RestoreRevision with deleted media: %v, want a media_ids ValidationError", err)
+	}
+	current, err := st.Projects.FindByID(ctx, project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Version != 2 || len(current.MediaIDs) != 0 {
+		t.Errorf("project changed by a rejected restore: version %d, media %v", current.Version, current.MediaIDs)
+	}
+
+	// Purged projects are recreated only from valid revisions, too.
+	if err := service.DeleteProject(id, nil, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.PurgeProject(id, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.RestoreRevision(id, 1, nil, "admin"); !errors.As(err, &validationErr) {
+		t.Fatalf("RestoreRevision of a purged project with deleted media: %v, want a ValidationError", err)
+	}
+	if _, err := service.RestoreRevision(id, 2, nil, "admin"); err != nil {
+		t.Errorf("RestoreRevision of a valid revision: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+// schedulerAuthor is the revision author of scheduled status changes.
+const schedulerAuthor = "scheduler"
+
+// ApplySchedule publishes drafts whose publish_at has passed and archives
+// published projects whose unpublish_at has passed, recording a revision for
+// each. The time that fired is cleared, so a later manual status change is
+// not undone by the next run. Projects edited concurrently are left for the
+// next run. It returns the number of projects changed.
+func (s *ProjectService) ApplySchedule(ctx context.Context, now time.Time) (int, error) {
+	drafts, err := s.repo.Find(ctx, store.ProjectQuery{Status: models.ProjectStatusDraft, PublishDue: now})
+	if err != nil {
+		return 0, err
+	}
+	published, err := s.repo.Find(ctx, store.ProjectQuery{Status: models.ProjectStatusPublished, UnpublishDue: now})
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, project := range append(drafts, published...) {
+		status := project.ScheduledStatus(now)
+		if status == project.Status {
+			continue
+		}
+
+		if project.Status == models.ProjectStatusDraft {
+			project.PublishAt = nil
+		}
+		if status == models.ProjectStatusArchived {
+			project.UnpublishAt = nil
+		}
+		project.Status = status
+		project.UpdatedAt = now
+		err := s.repo.Replace(ctx, &project)
+		if errors.Is(err, store.ErrVersionConflict) || errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return changed, err
+		}
+		if err := s.recordRevision(ctx, &project, project.Version, models.RevisionUpdate, schedulerAuthor, 0); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// ProjectScheduler applies scheduled status changes in the background.
+// Public endpoints also check publish_at and unpublish_at when they are
+// queried, so visibility is exact even between runs.
+type ProjectScheduler struct {
+	projects *ProjectService
+	interval time.Duration
+}
+
+func NewProjectScheduler(projects *ProjectService, interval time.Duration) *ProjectScheduler {
+	return &ProjectScheduler{
+		projects: projects,
+		interval: interval,
+	}
+}
+
+// Run applies the schedule immediately and then once per interval until ctx
+// is cancelled.
+func (s *ProjectScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		changed, err := s.projects.ApplySchedule(ctx, time.Now())
+		if err != nil {
+			log.Println("Failed to apply project schedule:", err)
+		} else if changed > 0 {
+			log.Printf("Changed the status of %d scheduled projects", changed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
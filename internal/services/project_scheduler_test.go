@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+func newTestProjectService() (*ProjectService, *store.Store) {
+	st := store.NewMemoryStore()
+	media := NewMediaService(st.Media, st.Projects, st.Posts, nil, 0, "")
+	return NewProjectService(st.Projects, st.ProjectRevisions, media), st
+}
+
+func TestApplyScheduleClearsFiredTimes(t *testing.T) {
+	ctx := context.Background()
+	service, st := newTestProjectService()
+	now := time.Now()
+	publishAt, unpublishAt := now.Add(time.Hour), now.Add(2*time.Hour)
+
+	project := &models.Project{Title: "Portfolio", Description: "A portfolio", PublishAt: &publishAt, UnpublishAt: &unpublishAt}
+	if err := service.CreateProject(project, "admin"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		at                       time.Time
+		status                   string
+		publishSet, unpublishSet bool
+	}{
+		{now, models.ProjectStatusDraft, true, true},
+		{publishAt, models.ProjectStatusPublished, false, true},
+		{unpublishAt, models.ProjectStatusArchived, false, false},
+	}
+	for _, tt := range tests {
+		if _, err := service.ApplySchedule(ctx, tt.at); err != nil {
+			t.Fatal(err)
+		}
+		got, err := st.Projects.FindByID(ctx, project.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != tt.status || (got.PublishAt != nil) != tt.publishSet || (got.UnpublishAt != nil) != tt.unpublishSet {
+			t.Errorf("at %v: status %s, publish_at %v, unpublish_at %v", tt.at, got.Status, got.PublishAt, got.UnpublishAt)
+		}
+	}
+}
+
+func TestApplyScheduleKeepsManualStatus(t *testing.T) {
+	ctx := context.Background()
+	service, st := newTestProjectService()
+	now := time.Now()
+	publishAt := now.Add(-time.Minute)
+
+	project := &models.Project{Title: "Portfolio", Description: "A portfolio", PublishAt: &publishAt}
+	if err := service.CreateProject(project, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := service.ApplySchedule(ctx, now); err != nil || changed != 1 {
+		t.Fatalf("ApplySchedule = %d, %v; want the draft published", changed, err)
+	}
+
+	// An editor takes the project back to draft after it was published.
+	if _, err := service.PatchProject(project.ID.Hex(), MergePatch, []byte(`{"status":"draft"}`), nil, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := service.ApplySchedule(ctx, now.Add(time.Minute)); err != nil || changed != 0 {
+		t.Fatalf("ApplySchedule = %d, %v; want the manual status kept", changed, err)
+	}
+	got, err := st.Projects.FindByID(ctx, project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != models.ProjectStatusDraft {
+		t.Errorf("status = %s, want draft", got.Status)
+	}
+}
@@ -1,108 +1,541 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
-	"portfolio-backend/internal/database"
+	"portfolio-backend/internal/jsonpatch"
 	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
 )
 
 type ProjectService struct {
-	db         *database.MongoDB
-	collection *mongo.Collection
+	repo      store.ProjectRepository
+	revisions store.ProjectRevisionRepository
+	media     *MediaService
 }
 
-func NewProjectService(db *database.MongoDB) *ProjectService {
+func NewProjectService(repo store.ProjectRepository, revisions store.ProjectRevisionRepository, media *MediaService) *ProjectService {
 	return &ProjectService{
-		db:         db,
-		collection: db.GetCollection("projects"),
+		repo:      repo,
+		revisions: revisions,
+		media:     media,
 	}
 }
 
-func (s *ProjectService) CreateProject(project *models.Project) error {
+// CreateProject stores a new project and records its first revision under
+// author. Projects start as drafts unless a status is given, get a slug
+// derived from the title unless one is given, and are placed first in the
+// manual order unless given a position.
+func (s *ProjectService) CreateProject(project *models.Project, author string) error {
+	if project.Status == "" {
+		project.Status = models.ProjectStatusDraft
+	}
+	if err := validateProject(project.Fields()); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := s.media.checkGallery(ctx, project.MediaIDs); err != nil {
+		return err
+	}
+
+	if project.ID.IsZero() {
+		project.ID = primitive.NewObjectID()
+	}
 	project.CreatedAt = time.Now()
 	project.UpdatedAt = time.Now()
+	project.Version = 1
+	project.DeletedAt = nil
+	project.PreviousSlugs = nil
+
+	if project.Position == 0 {
+		position, err := s.firstPosition(ctx)
+		if err != nil {
+			return err
+		}
+		project.Position = position
+	}
+	if err := s.assignSlug(ctx, project, nil); err != nil {
+		return err
+	}
+	if err := renderDescription(project); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, project); err != nil {
+		return slugConflict(err)
+	}
+	return s.recordRevision(ctx, project, project.Version, models.RevisionCreate, author, 0)
+}
+
+// ProjectListParams are the query parameters accepted when listing projects.
+type ProjectListParams struct {
+	Page         int       `form:"page"`
+	Limit        int       `form:"limit"`
+	Cursor       string    `form:"cursor"`
+	Category     string    `form:"category"`
+	Technologies string    `form:"technologies"` // comma-separated
+	TechMatch    string    `form:"tech_match"`   // any (default) or all
+	Featured     *bool     `form:"featured"`
+	Status       string    `form:"status"` // ignored for public listings
+	CreatedSince time.Time `form:"created_since" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedUntil time.Time `form:"created_until" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedSince time.Time `form:"updated_since" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedUntil time.Time `form:"updated_until" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort         string    `form:"sort"`
+	Order        string    `form:"order"`
+}
+
+// GetProjects lists projects matching params. With a cursor the listing
+// continues after it; otherwise the page number is used. Unless
+// includeUnpublished is set, only currently public projects are listed.
+func (s *ProjectService) GetProjects(params ProjectListParams, includeUnpublished bool) ([]models.ProjectResponse, *models.Pagination, error) {
+	query := store.ProjectQuery{}
+	if !includeUnpublished {
+		query.PublicAt = time.Now()
+		params.Status = ""
+	}
+	return s.listProjects(params, query)
+}
+
+// GetTrashedProjects lists the project trash like GetProjects, most recently
+// deleted first by default.
+func (s *ProjectService) GetTrashedProjects(params ProjectListParams) ([]models.ProjectResponse, *models.Pagination, error) {
+	return s.listProjects(params, store.ProjectQuery{Trashed: true})
+}
+
+// listProjects applies params to the base query and runs it.
+func (s *ProjectService) listProjects(params ProjectListParams, query store.ProjectQuery) ([]models.ProjectResponse, *models.Pagination, error) {
+	sortFields := store.ProjectSortFields
+	trashed := query.Trashed
+	query = store.ProjectQuery{
+		Trashed:      trashed,
+		PublicAt:     query.PublicAt,
+		Category:     strings.TrimSpace(params.Category),
+		Featured:     params.Featured,
+		CreatedSince: params.CreatedSince,
+		CreatedUntil: params.CreatedUntil,
+		UpdatedSince: params.UpdatedSince,
+		UpdatedUntil: params.UpdatedUntil,
+		SortBy:       "created_at",
+	}
+	if trashed {
+		sortFields = store.TrashedProjectSortFields
+		query.SortBy = "deleted_at"
+	}
+
+	for _, tech := range strings.Split(params.Technologies, ",") {
+		if tech = strings.TrimSpace(tech); tech != "" {
+			query.Technologies = append(query.Technologies, tech)
+		}
+	}
+	if params.Status != "" {
+		if !slices.Contains(models.ProjectStatuses, params.Status) {
+			return nil, nil, fmt.Errorf("%w: unknown status %q", ErrInvalidListParams, params.Status)
+		}
+		query.Status = params.Status
+	}
+
+	switch strings.ToLower(params.TechMatch) {
+	case "", "any":
+	case "all":
+		query.AllTechnologies = true
+	default:
+		return nil, nil, fmt.Errorf("%w: tech_match must be any or all", ErrInvalidListParams)
+	}
+
+	if params.Sort != "" {
+		if !slices.Contains(sortFields, params.Sort) {
+			return nil, nil, fmt.Errorf("%w: unknown sort field %q", ErrInvalidListParams, params.Sort)
+		}
+		query.SortBy = params.Sort
+	}
+	// Dates default to newest first, title and manual order to ascending.
+	asc, err := parseSortOrder(params.Order, query.SortBy == "title" || query.SortBy == "position")
+	if err != nil {
+		return nil, nil, err
+	}
+	query.SortAsc = asc
+
+	limit, page := normalizePage(params.Limit, params.Page)
+	if params.Cursor != "" {
+		if query.After, err = store.DecodeCursor(params.Cursor); err != nil {
+			return nil, nil, err
+		}
+		page = 0
+	} else if query.Skip, err = pageSkip(limit, page); err != nil {
+		return nil, nil, err
+	}
+
+	ctx := context.Background()
+	total, err := s.repo.Count(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Fetch one extra to tell whether another page follows.
+	query.Limit = int64(limit + 1)
+	projects, err := s.repo.Find(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := newPagination(total, limit, page, len(projects) > limit)
+	if pagination.HasMore {
+		projects = projects[:limit]
+		last := projects[len(projects)-1]
+		pagination.NextCursor = store.EncodeCursor(store.Cursor{
+			Value: store.CursorValue(store.ProjectSortValue(&last, query.SortBy)),
+			ID:    last.ID,
+		})
+	}
+
+	responses, err := s.toResponses(ctx, projects)
+	if err != nil {
+		return nil, nil, err
+	}
+	return responses, pagination, nil
+}
 
-	_, err := s.collection.InsertOne(context.Background(), project)
-	return err
+// GetProjectFacets returns the categories and technologies in use, with the
+// number of projects for each. Unless includeUnpublished is set, only
+// currently public projects are counted.
+func (s *ProjectService) GetProjectFacets(includeUnpublished bool) (*models.ProjectFacets, error) {
+	query := store.ProjectQuery{}
+	if !includeUnpublished {
+		query.PublicAt = time.Now()
+	}
+
+	facets, err := s.repo.Facets(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	if facets.Categories == nil {
+		facets.Categories = []models.FacetCount{}
+	}
+	if facets.Technologies == nil {
+		facets.Technologies = []models.FacetCount{}
+	}
+	return facets, nil
 }
 
-func (s *ProjectService) GetAllProjects() ([]models.ProjectResponse, error) {
-	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
-	cursor, err := s.collection.Find(context.Background(), bson.M{}, opts)
+// SearchProjects runs a full-text search over title, description,
+// technologies and category of the currently public projects.
+func (s *ProjectService) SearchProjects(params SearchParams) ([]models.ProjectSearchResult, error) {
+	terms, err := params.normalize()
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(context.Background())
 
-	var projects []models.ProjectResponse
-	if err = cursor.All(context.Background(), &projects); err != nil {
+	ctx := context.Background()
+	matches, err := s.repo.Search(ctx, params.Query, store.ProjectQuery{
+		PublicAt: time.Now(),
+		Limit:    int64(params.Limit),
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return projects, nil
+	projects := make([]models.Project, len(matches))
+	for i := range matches {
+		projects[i] = matches[i].Project
+	}
+	responses, err := s.toResponses(ctx, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.ProjectSearchResult, 0, len(matches))
+	for i := range matches {
+		project := &matches[i].Project
+		results = append(results, models.ProjectSearchResult{
+			Project: responses[i],
+			Score:   matches[i].Score,
+			Highlights: highlightFields(terms, map[string]string{
+				"title":        project.Title,
+				"description":  project.Description,
+				"technologies": strings.Join(project.Technologies, ", "),
+				"category":     project.Category,
+			}),
+		})
+	}
+
+	return results, nil
 }
 
+// GetFeaturedProjects lists the currently public featured projects in
+// their manual order.
 func (s *ProjectService) GetFeaturedProjects() ([]models.ProjectResponse, error) {
-	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
-	cursor, err := s.collection.Find(context.Background(), bson.M{"featured": true}, opts)
+	featured := true
+	ctx := context.Background()
+	projects, err := s.repo.Find(ctx, store.ProjectQuery{
+		Featured: &featured,
+		PublicAt: time.Now(),
+		SortBy:   "position",
+		SortAsc:  true,
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(context.Background())
 
-	var projects []models.ProjectResponse
-	if err = cursor.All(context.Background(), &projects); err != nil {
+	return s.toResponses(ctx, projects)
+}
+
+// GetProjectByID returns a project. Unless includeUnpublished is set,
+// projects that are not currently public are reported as store.ErrNotFound.
+func (s *ProjectService) GetProjectByID(id string, includeUnpublished bool) (*models.ProjectResponse, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
 		return nil, err
 	}
 
-	return projects, nil
+	ctx := context.Background()
+	project, err := s.repo.FindByID(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if !includeUnpublished && !project.IsPublic(time.Now()) {
+		return nil, store.ErrNotFound
+	}
+
+	return s.toResponse(ctx, project)
 }
 
-func (s *ProjectService) GetProjectByID(id string) (*models.ProjectResponse, error) {
+// UpdateProject replaces the writable fields of a project; an empty status
+// or a zero position keeps the current one. ifMatch lists the versions the
+// client expects (nil for any).
+func (s *ProjectService) UpdateProject(id string, project *models.Project, ifMatch []int64, author string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
+		return store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	existing, err := s.repo.FindByID(ctx, objectID)
+	if err != nil {
+		return err
+	}
+	if project.Status == "" {
+		project.Status = existing.Status
+	}
+	if project.Position == 0 {
+		project.Position = existing.Position
+	}
+	if err := validateProject(project.Fields()); err != nil {
+		return err
+	}
+	if err := s.media.checkGallery(ctx, project.MediaIDs); err != nil {
+		return err
+	}
+	if err := checkVersion(existing.Version, ifMatch); err != nil {
+		return err
+	}
+
+	project.ID = objectID
+	project.CreatedAt = existing.CreatedAt
+	project.Version = existing.Version
+	project.UpdatedAt = time.Now()
+	project.DeletedAt = nil
+
+	if err := s.assignSlug(ctx, project, existing); err != nil {
+		return err
+	}
+	if err := renderDescription(project); err != nil {
+		return err
+	}
+	if err := s.repo.Replace(ctx, project); err != nil {
+		return slugConflict(err)
+	}
+	return s.recordRevision(ctx, project, project.Version, models.RevisionUpdate, author, 0)
+}
+
+// PatchKind selects the patch format accepted by PatchProject.
+type PatchKind int
+
+const (
+	// MergePatch is a JSON Merge Patch (RFC 7396).
+	MergePatch PatchKind = iota
+	// JSONPatch is a JSON Patch (RFC 6902).
+	JSONPatch
+)
+
+// PatchProject applies a patch to the writable fields of a project and
+// returns the updated project. An id that is not a valid ObjectID is
+// reported as store.ErrNotFound.
+func (s *ProjectService) PatchProject(id string, kind PatchKind, patch []byte, ifMatch []int64, author string) (*models.Project, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	project, err := s.repo.FindByID(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(project.Version, ifMatch); err != nil {
 		return nil, err
 	}
 
-	var project models.ProjectResponse
-	err = s.collection.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&project)
+	// Patches operate on the JSON form of the writable fields.
+	data, err := json.Marshal(project.Fields())
 	if err != nil {
 		return nil, err
 	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
 
-	return &project, nil
+	switch kind {
+	case JSONPatch:
+		doc, err = jsonpatch.Apply(doc, patch)
+	default:
+		doc, err = jsonpatch.MergePatch(doc, patch)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err = json.Marshal(doc); err != nil {
+		return nil, err
+	}
+	var fields models.ProjectFields
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("%w: %v", jsonpatch.ErrInvalidPatch, err)
+	}
+	if err := validateProject(fields); err != nil {
+		return nil, err
+	}
+	if err := s.media.checkGallery(ctx, fields.MediaIDs); err != nil {
+		return nil, err
+	}
+
+	existing := *project
+	project.SetFields(fields)
+	project.UpdatedAt = time.Now()
+	if err := s.assignSlug(ctx, project, &existing); err != nil {
+		return nil, err
+	}
+	if err := renderDescription(project); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Replace(ctx, project); err != nil {
+		return nil, slugConflict(err)
+	}
+	if err := s.recordRevision(ctx, project, project.Version, models.RevisionUpdate, author, 0); err != nil {
+		return nil, err
+	}
+	return project, nil
 }
 
-func (s *ProjectService) UpdateProject(id string, project *models.Project) error {
+// DeleteProject moves a project to the trash and records a delete revision.
+func (s *ProjectService) DeleteProject(id string, ifMatch []int64, author string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	project, err := s.repo.FindByID(ctx, objectID)
 	if err != nil {
 		return err
 	}
+	if err := checkVersion(project.Version, ifMatch); err != nil {
+		return err
+	}
 
-	project.UpdatedAt = time.Now()
-	project.ID = objectID
+	now := time.Now()
+	if err := s.repo.Trash(ctx, objectID, project.Version, now); err != nil {
+		return err
+	}
+	project.DeletedAt = &now
+	project.Version++
+	return s.recordRevision(ctx, project, project.Version, models.RevisionDelete, author, 0)
+}
 
-	_, err = s.collection.ReplaceOne(
-		context.Background(),
-		bson.M{"_id": objectID},
-		project,
-	)
-	return err
+// RestoreProject takes a project out of the trash and records a restore
+// revision pointing at its delete revision. ifMatch lists the versions the
+// client expects (nil for any).
+func (s *ProjectService) RestoreProject(id string, ifMatch []int64, author string) (*models.Project, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	project, err := s.repo.FindTrashedByID(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(project.Version, ifMatch); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Restore(ctx, objectID, project.Version); err != nil {
+		return nil, err
+	}
+	trashedVersion := project.Version
+	project.DeletedAt = nil
+	project.Version++
+	if err := s.recordRevision(ctx, project, project.Version, models.RevisionRestore, author, trashedVersion); err != nil {
+		return nil, err
+	}
+	return project, nil
 }
 
-func (s *ProjectService) DeleteProject(id string) error {
+// PurgeProject permanently deletes a project from the trash. Its revision
+// history is kept.
+func (s *ProjectService) PurgeProject(id string, ifMatch []int64) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return store.ErrNotFound
+	}
+
+	ctx := context.Background()
+	project, err := s.repo.FindTrashedByID(ctx, objectID)
 	if err != nil {
 		return err
 	}
+	if err := checkVersion(project.Version, ifMatch); err != nil {
+		return err
+	}
 
-	_, err = s.collection.DeleteOne(context.Background(), bson.M{"_id": objectID})
-	return err
+	return s.repo.Purge(ctx, objectID, project.Version)
+}
+
+// toResponses converts projects to responses with their galleries.
+func (s *ProjectService) toResponses(ctx context.Context, projects []models.Project) ([]models.ProjectResponse, error) {
+	ids := make([][]primitive.ObjectID, len(projects))
+	for i := range projects {
+		ids[i] = projects[i].MediaIDs
+	}
+	galleries, err := s.media.galleries(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.ProjectResponse, 0, len(projects))
+	for i := range projects {
+		response := projects[i].ToResponse()
+		response.Gallery = galleries[i]
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (s *ProjectService) toResponse(ctx context.Context, project *models.Project) (*models.ProjectResponse, error) {
+	responses, err := s.toResponses(ctx, []models.Project{*project})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
 }
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/slug"
+	"portfolio-backend/internal/store"
+)
+
+// errSlugTaken is reported when a requested slug belongs to another project,
+// or another post.
+var errSlugTaken = &ValidationError{Fields: map[string]string{"slug": "is already in use"}}
+
+// slugRepository is implemented by the repositories of content with slugs.
+type slugRepository interface {
+	SlugTaken(ctx context.Context, slug string, except primitive.ObjectID) (bool, error)
+}
+
+// GetProjectBySlug returns the public project with slug. When slug is one
+// the project had before a rename, the project is nil and redirect holds
+// its current slug.
+func (s *ProjectService) GetProjectBySlug(value string) (project *models.ProjectResponse, redirect string, err error) {
+	ctx := context.Background()
+	now := time.Now()
+
+	found, err := s.repo.FindBySlug(ctx, value)
+	if err == nil && found.IsPublic(now) {
+		response, err := s.toResponse(ctx, found)
+		return response, "", err
+	}
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, "", err
+	}
+
+	found, err = s.repo.FindByPreviousSlug(ctx, value)
+	if err != nil {
+		return nil, "", err
+	}
+	if !found.IsPublic(now) {
+		return nil, "", store.ErrNotFound
+	}
+	return nil, found.Slug, nil
+}
+
+// assignSlug settles project.Slug before it is written; existing is the
+// stored project, or nil for a new one. A slug the client chose is kept
+// unless another project has it. Otherwise the slug is derived from the
+// title, and derived again when the title changes. A replaced slug is
+// added to PreviousSlugs so that it keeps resolving.
+func (s *ProjectService) assignSlug(ctx context.Context, project, existing *models.Project) error {
+	requested := project.Slug
+	current := ""
+	if existing != nil {
+		current = existing.Slug
+		project.PreviousSlugs = existing.PreviousSlugs
+	}
+
+	switch {
+	case requested != "" && requested != current:
+		taken, err := s.repo.SlugTaken(ctx, requested, project.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errSlugTaken
+		}
+	case current == "" || (project.Title != existing.Title && derivedSlug(current, existing.Title, project.ID)):
+		generated, err := uniqueSlug(ctx, s.repo, baseSlug(project.Title, project.ID), project.ID)
+		if err != nil {
+			return err
+		}
+		project.Slug = generated
+	default:
+		project.Slug = current
+	}
+
+	if current != "" && project.Slug != current {
+		previous := slices.DeleteFunc(slices.Clone(project.PreviousSlugs), func(s string) bool {
+			return s == current || s == project.Slug
+		})
+		project.PreviousSlugs = append(previous, current)
+	}
+	return nil
+}
+
+// uniqueSlug returns base, or base with the lowest numeric suffix from 2 up
+// that nothing in repo other than id uses.
+func uniqueSlug(ctx context.Context, repo slugRepository, base string, id primitive.ObjectID) (string, error) {
+	candidate := base
+	for n := 2; ; n++ {
+		taken, err := repo.SlugTaken(ctx, candidate, id)
+		if err != nil || !taken {
+			return candidate, err
+		}
+		candidate = slug.WithSuffix(base, n)
+	}
+}
+
+// baseSlug derives a slug from title, falling back to the project id for
+// titles with nothing to transliterate.
+func baseSlug(title string, id primitive.ObjectID) string {
+	if s := slug.Make(title); s != "" {
+		return s
+	}
+	return id.Hex()
+}
+
+// derivedSlug reports whether value is what uniqueSlug would have made from
+// title, as opposed to a slug the client chose.
+func derivedSlug(value, title string, id primitive.ObjectID) bool {
+	base := baseSlug(title, id)
+	if value == base {
+		return true
+	}
+	i := strings.LastIndexByte(value, '-')
+	if i < 0 {
+		return false
+	}
+	n, err := strconv.Atoi(value[i+1:])
+	return err == nil && n >= 2 && value == slug.WithSuffix(base, n)
+}
+
+// slugConflict turns a duplicate key error from a write, which means another
+// project or post took the slug since it was checked, into errSlugTaken.
+func slugConflict(err error) error {
+	if errors.Is(err, store.ErrDuplicate) {
+		return errSlugTaken
+	}
+	return err
+}
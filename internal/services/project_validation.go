@@ -0,0 +1,98 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/slug"
+)
+
+const (
+	maxTitleLength       = 200
+	maxDescriptionLength = 50000
+	maxTechnologyLength  = 50
+)
+
+// ValidationError lists invalid fields by JSON name with the reason for each.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "invalid fields: " + strings.Join(names, ", ")
+}
+
+// validateProject checks the writable fields of a project.
+func validateProject(f models.ProjectFields) error {
+	invalid := make(map[string]string)
+
+	switch {
+	case strings.TrimSpace(f.Title) == "":
+		invalid["title"] = "is required"
+	case utf8.RuneCountInString(f.Title) > maxTitleLength:
+		invalid["title"] = fmt.Sprintf("must be at most %d characters", maxTitleLength)
+	}
+	if f.Slug != "" && !slug.Valid(f.Slug) {
+		invalid["slug"] = fmt.Sprintf("must be lowercase letters and digits separated by single hyphens, at most %d characters", slug.MaxLength)
+	}
+	switch {
+	case strings.TrimSpace(f.Description) == "":
+		invalid["description"] = "is required"
+	case utf8.RuneCountInString(f.Description) > maxDescriptionLength:
+		invalid["description"] = fmt.Sprintf("must be at most %d characters", maxDescriptionLength)
+	}
+
+	for name, value := range map[string]string{
+		"image_url":  f.ImageURL,
+		"live_url":   f.LiveURL,
+		"github_url": f.GitHubURL,
+	} {
+		if value != "" && !isHTTPURL(value) {
+			invalid[name] = "must be an absolute http or https URL"
+		}
+	}
+
+	if !slices.Contains(models.ProjectStatuses, f.Status) {
+		invalid["status"] = "must be one of " + strings.Join(models.ProjectStatuses, ", ")
+	}
+	if f.PublishAt != nil && f.UnpublishAt != nil && !f.UnpublishAt.After(*f.PublishAt) {
+		invalid["unpublish_at"] = "must be after publish_at"
+	}
+	// 0 stands for no position, so positions are positive.
+	if f.Position < 0 {
+		invalid["position"] = "must be positive"
+	}
+
+	seen := make(map[string]bool)
+	for _, tech := range f.Technologies {
+		switch {
+		case strings.TrimSpace(tech) == "":
+			invalid["technologies"] = "must not contain empty values"
+		case utf8.RuneCountInString(tech) > maxTechnologyLength:
+			invalid["technologies"] = fmt.Sprintf("values must be at most %d characters", maxTechnologyLength)
+		case seen[tech]:
+			invalid["technologies"] = fmt.Sprintf("contains %q more than once", tech)
+		}
+		seen[tech] = true
+	}
+
+	if len(invalid) > 0 {
+		return &ValidationError{Fields: invalid}
+	}
+	return nil
+}
+
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
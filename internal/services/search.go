@@ -0,0 +1,42 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"portfolio-backend/internal/search"
+)
+
+// snippetLength is the approximate length of highlighted excerpts.
+const snippetLength = 160
+
+// ErrEmptySearchQuery is returned when a search query has no searchable words.
+var ErrEmptySearchQuery = errors.New("search query must contain at least one word")
+
+// SearchParams are the query parameters accepted by search endpoints.
+type SearchParams struct {
+	Query string `form:"q"`
+	Limit int    `form:"limit"`
+}
+
+// normalize validates the query and clamps the limit.
+func (p *SearchParams) normalize() ([]string, error) {
+	p.Query = strings.TrimSpace(p.Query)
+	terms := search.Terms(p.Query)
+	if len(terms) == 0 {
+		return nil, ErrEmptySearchQuery
+	}
+	p.Limit, _ = normalizePage(p.Limit, 1)
+	return terms, nil
+}
+
+// highlightFields returns excerpts of the fields containing any of terms.
+func highlightFields(terms []string, fields map[string]string) map[string]string {
+	highlights := make(map[string]string)
+	for name, text := range fields {
+		if snippet := search.Highlight(text, terms, snippetLength); snippet != "" {
+			highlights[name] = snippet
+		}
+	}
+	return highlights
+}
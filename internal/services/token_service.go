@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"portfolio-backend/internal/middleware"
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+// mfaChallengeExpiry is how long a user has to enter their second factor
+// after the password step of a login.
+const mfaChallengeExpiry = 5 * time.Minute
+
+var (
+	ErrInvalidMFAChallenge = errors.New("invalid or expired MFA challenge")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+)
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// TokenService issues short-lived access tokens together with rotating
+// refresh tokens, and maintains the access token revocation list.
+type TokenService struct {
+	refreshTokens store.RefreshTokenRepository
+	revokedTokens store.RevokedTokenRepository
+	users         store.UserRepository
+	keys          *middleware.KeySet
+	accessExpiry  time.Duration
+	refreshExpiry time.Duration
+}
+
+func NewTokenService(
+	refreshTokens store.RefreshTokenRepository,
+	revokedTokens store.RevokedTokenRepository,
+	users store.UserRepository,
+	keys *middleware.KeySet,
+	accessExpiry, refreshExpiry time.Duration,
+) *TokenService {
+	return &TokenService{
+		refreshTokens: refreshTokens,
+		revokedTokens: revokedTokens,
+		users:         users,
+		keys:          keys,
+		accessExpiry:  accessExpiry,
+		refreshExpiry: refreshExpiry,
+	}
+}
+
+// IssueTokens starts a new token family for a freshly authenticated user.
+func (s *TokenService) IssueTokens(user *models.User) (*TokenPair, error) {
+	familyID, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(user, familyID)
+}
+
+// Refresh exchanges a refresh token for a new token pair. Each refresh token
+// can be used once; presenting an already rotated token revokes its family.
+func (s *TokenService) Refresh(refreshToken string) (*TokenPair, *models.User, error) {
+	ctx := context.Background()
+
+	token, err := s.refreshTokens.FindByHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if token.RevokedAt != nil || now.After(token.ExpiresAt) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if token.RotatedAt != nil {
+		return nil, nil, s.revokeReusedFamily(token.FamilyID, now)
+	}
+
+	rotated, err := s.refreshTokens.MarkRotated(ctx, token.ID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !rotated {
+		// Someone else used the token between our read and write
+		return nil, nil, s.revokeReusedFamily(token.FamilyID, now)
+	}
+
+	user, err := s.users.FindByUsername(ctx, token.Username)
+	if err != nil || user.Disabled {
+		if revokeErr := s.refreshTokens.RevokeFamily(ctx, token.FamilyID, now); revokeErr != nil {
+			return nil, nil, revokeErr
+		}
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	pair, err := s.issue(user, token.FamilyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, user, nil
+}
+
+// IssueMFAChallenge returns the token that lets the user complete a two-factor
+// login, and its lifetime in seconds.
+func (s *TokenService) IssueMFAChallenge(user *models.User) (string, int64, error) {
+	token, err := middleware.GenerateMFAChallengeToken(user.Username, s.keys, mfaChallengeExpiry)
+	if err != nil {
+		return "", 0, err
+	}
+	return token, int64(mfaChallengeExpiry.Seconds()), nil
+}
+
+// ParseMFAChallenge returns the username an MFA challenge token was issued for.
+func (s *TokenService) ParseMFAChallenge(token string) (string, error) {
+	username, err := middleware.ParseMFAChallengeToken(token, s.keys)
+	if err != nil {
+		return "", ErrInvalidMFAChallenge
+	}
+	return username, nil
+}
+
+// RevokeAccessToken adds the token's jti to the revocation list until expiresAt.
+func (s *TokenService) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	if jti == "" {
+		return nil
+	}
+	return s.revokedTokens.Add(context.Background(), &models.RevokedToken{
+		JTI:       jti,
+		RevokedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	})
+}
+
+// RevokeRefreshToken revokes the family the refresh token belongs to. Unknown
+// tokens are ignored so logout is idempotent.
+func (s *TokenService) RevokeRefreshToken(refreshToken string) error {
+	token, err := s.refreshTokens.FindByHash(context.Background(), hashToken(refreshToken))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.refreshTokens.RevokeFamily(context.Background(), token.FamilyID, time.Now())
+}
+
+// RevokeUserSessions revokes every refresh token issued to the user. Access
+// tokens are cut off through the user's SessionsRevokedAt, see IsRevoked.
+func (s *TokenService) RevokeUserSessions(username string) error {
+	return s.refreshTokens.RevokeAllForUser(context.Background(), username, time.Now())
+}
+
+// IsRevoked implements middleware.RevocationChecker. Besides the jti list it
+// rejects tokens of disabled or deleted users and tokens issued before the
+// user's sessions were revoked, e.g. by a password reset.
+func (s *TokenService) IsRevoked(claims *middleware.Claims) (bool, error) {
+	if claims.ID != "" {
+		revoked, err := s.revokedTokens.IsRevoked(context.Background(), claims.ID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	user, err := s.users.FindByUsername(context.Background(), claims.Username)
+	if errors.Is(err, store.ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if user.Disabled {
+		return true, nil
+	}
+
+	// iat only has second precision
+	if user.SessionsRevokedAt != nil && claims.IssuedAt != nil &&
+		claims.IssuedAt.Time.Before(user.SessionsRevokedAt.Truncate(time.Second)) {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (s *TokenService) issue(user *models.User, familyID string) (*TokenPair, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := middleware.GenerateToken(user.Username, string(user.Role), jti, s.keys, s.accessExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.refreshTokens.Create(context.Background(), &models.RefreshToken{
+		TokenHash: hashToken(refreshToken),
+		FamilyID:  familyID,
+		Username:  user.Username,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.refreshExpiry),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.accessExpiry.Seconds()),
+	}, nil
+}
+
+func (s *TokenService) revokeReusedFamily(familyID string, now time.Time) error {
+	if err := s.refreshTokens.RevokeFamily(context.Background(), familyID, now); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// randomToken returns n random bytes encoded as URL-safe base64.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
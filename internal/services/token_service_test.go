@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"portfolio-backend/internal/middleware"
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+func newTestTokenService(t *testing.T) (*TokenService, *models.User) {
+	t.Helper()
+	st := store.NewMemoryStore()
+	user := &models.User{Username: "admin", Role: models.RoleOwner}
+	if err := st.Users.Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	keys := middleware.NewHMACKeySet("test-secret")
+	return NewTokenService(st.RefreshTokens, st.RevokedTokens, st.Users, keys, 15*time.Minute, time.Hour), user
+}
+
+func TestTokenServiceRefreshRotates(t *testing.T) {
+	service, user := newTestTokenService(t)
+
+	first, err := service.IssueTokens(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, refreshed, err := service.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed.Username != "admin" {
+		t.Errorf("Refresh returned user %q", refreshed.Username)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == first.AccessToken {
+		t.Error("Refresh did not issue new tokens")
+	}
+	if _, _, err := service.Refresh(second.RefreshToken); err != nil {
+		t.Errorf("Refresh with the rotated token: %v", err)
+	}
+}
+
+func TestTokenServiceRefreshDetectsReuse(t *testing.T) {
+	service, user := newTestTokenService(t)
+
+	first, err := service.IssueTokens(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _, err := service.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := service.Refresh(first.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Refresh with a rotated token: %v, want ErrRefreshTokenReused", err)
+	}
+	// Reuse revokes the whole family, including the token issued in its place.
+	if _, _, err := service.Refresh(second.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Refresh after reuse: %v, want ErrInvalidRefreshToken", err)
+	}
+
+	// Other families are unaffected.
+	other, err := service.IssueTokens(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.Refresh(other.RefreshToken); err != nil {
+		t.Errorf("Refresh in another family: %v", err)
+	}
+}
+
+func TestTokenServiceRefreshRejectsUnknownAndRevokedTokens(t *testing.T) {
+	service, user := newTestTokenService(t)
+
+	if _, _, err := service.Refresh("unknown"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Refresh with an unknown token: %v, want ErrInvalidRefreshToken", err)
+	}
+
+	pair, err := service.IssueTokens(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.RevokeRefreshToken(pair.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.Refresh(pair.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Refresh with a revoked token: %v, want ErrInvalidRefreshToken", err)
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 test vectors,
+// "12345678901234567890", base32 encoded.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// The RFC 6238 appendix B SHA-1 vectors, truncated to the last 6 of their 8
+// digits.
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestTOTPCode(t *testing.T) {
+	for _, tt := range rfc6238Vectors {
+		code, err := TOTPCode(rfc6238Secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("TOTPCode(%d): %v", tt.unix, err)
+		}
+		if code != tt.code {
+			t.Errorf("TOTPCode(%d) = %s, want %s", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	for _, tt := range rfc6238Vectors {
+		now := time.Unix(tt.unix, 0)
+		step, ok := ValidateTOTP(rfc6238Secret, tt.code, now)
+		if !ok || step != totpStep(now) {
+			t.Errorf("ValidateTOTP(%s, %d) = %d, %v; want %d, true", tt.code, tt.unix, step, ok, totpStep(now))
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := totpStep(now)
+
+	tests := []struct {
+		offset time.Duration
+		ok     bool
+	}{
+		{-2 * totpPeriod * time.Second, false},
+		{-totpPeriod * time.Second, true},
+		{0, true},
+		{totpPeriod * time.Second, true},
+		{2 * totpPeriod * time.Second, false},
+	}
+	for _, tt := range tests {
+		code, err := TOTPCode(rfc6238Secret, now.Add(tt.offset))
+		if err != nil {
+			t.Fatal(err)
+		}
+		step, ok := ValidateTOTP(rfc6238Secret, code, now)
+		if ok != tt.ok {
+			t.Errorf("code from %v away: ok = %v, want %v", tt.offset, ok, tt.ok)
+			continue
+		}
+		if want := current + int64(tt.offset/(totpPeriod*time.Second)); ok && step != want {
+			t.Errorf("code from %v away: step = %d, want %d", tt.offset, step, want)
+		}
+	}
+}
+
+func TestValidateTOTPRejectsMalformedCodes(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "abcdef"} {
+		if _, ok := ValidateTOTP(rfc6238Secret, code, now); ok {
+			t.Errorf("ValidateTOTP(%q) accepted", code)
+		}
+	}
+	if _, ok := ValidateTOTP("not base32!", "287082", now); ok {
+		t.Error("ValidateTOTP accepted an invalid secret")
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"portfolio-backend/internal/store"
+)
+
+// ErrTrashed is returned when an operation needs an item that is in the
+// trash to be restored first.
+var ErrTrashed = errors.New("item is in the trash")
+
+// TrashPurger permanently deletes projects and contacts that have been in
+// the trash for longer than the retention period.
+type TrashPurger struct {
+	projects  store.ProjectRepository
+	contacts  store.ContactRepository
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewTrashPurger(projects store.ProjectRepository, contacts store.ContactRepository, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		projects:  projects,
+		contacts:  contacts,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// PurgeExpired deletes the items trashed before the retention period and
+// reports how many of each were removed.
+func (p *TrashPurger) PurgeExpired(ctx context.Context) (projects, contacts int64, err error) {
+	cutoff := time.Now().Add(-p.retention)
+
+	if projects, err = p.projects.PurgeTrashed(ctx, cutoff); err != nil {
+		return 0, 0, err
+	}
+	if contacts, err = p.contacts.PurgeTrashed(ctx, cutoff); err != nil {
+		return projects, 0, err
+	}
+	return projects, contacts, nil
+}
+
+// Run purges expired items immediately and then once per interval until ctx
+// is cancelled.
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		projects, contacts, err := p.PurgeExpired(ctx)
+		if err != nil {
+			log.Println("Failed to purge trash:", err)
+		} else if projects > 0 || contacts > 0 {
+			log.Printf("Purged %d projects and %d contacts from the trash", projects, contacts)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
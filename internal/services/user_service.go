@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUserDisabled       = errors.New("user is disabled")
+	ErrUsernameTaken      = errors.New("username already exists")
+	ErrInvalidRole        = errors.New("invalid role")
+)
+
+// dummyPasswordHash is compared against when the username does not exist so
+// that unknown and known usernames take roughly the same time to reject.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+type UserService struct {
+	repo store.UserRepository
+}
+
+func NewUserService(repo store.UserRepository) *UserService {
+	return &UserService{
+		repo: repo,
+	}
+}
+
+// CreateUser hashes the password and stores a new user.
+func (s *UserService) CreateUser(username, password, displayName, email string, role models.Role) (*models.User, error) {
+	username = normalizeUsername(username)
+	if username == "" || password == "" {
+		return nil, errors.New("username and password are required")
+	}
+	if !role.Valid() {
+		return nil, ErrInvalidRole
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	if displayName == "" {
+		displayName = username
+	}
+
+	user := &models.User{
+		Username:     username,
+		PasswordHash: string(hash),
+		DisplayName:  displayName,
+		Email:        normalizeEmail(email),
+		Role:         role,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := s.repo.Create(context.Background(), user); err != nil {
+		if errors.Is(err, store.ErrDuplicate) {
+			return nil, ErrUsernameTaken
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Authenticate verifies the credentials. It does not record the login, since
+// a second factor may still be required; call RecordLogin once the login is
+// complete.
+func (s *UserService) Authenticate(username, password string) (*models.User, error) {
+	user, err := s.repo.FindByUsername(context.Background(), normalizeUsername(username))
+	if errors.Is(err, store.ErrNotFound) {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	return user, nil
+}
+
+// RecordLogin stores the time of a completed login on the user.
+func (s *UserService) RecordLogin(user *models.User) error {
+	now := time.Now()
+	if err := s.repo.UpdateLastLogin(context.Background(), user.ID, now); err != nil {
+		return err
+	}
+	user.LastLoginAt = &now
+	return nil
+}
+
+func (s *UserService) GetUserByUsername(username string) (*models.User, error) {
+	return s.repo.FindByUsername(context.Background(), normalizeUsername(username))
+}
+
+// ChangePassword replaces the password after checking the current one. All
+// existing sessions of the user are invalidated.
+func (s *UserService) ChangePassword(username, currentPassword, newPassword string) (*models.User, error) {
+	user, err := s.repo.FindByUsername(context.Background(), normalizeUsername(username))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if currentPassword == newPassword {
+		return nil, &PasswordStrengthError{Reason: "must differ from the current password"}
+	}
+
+	if err := s.SetPassword(user, newPassword); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetPassword validates and stores a new password for the user and marks
+// every access token issued so far as revoked.
+func (s *UserService) SetPassword(user *models.User, password string) error {
+	if err := ValidatePasswordStrength(password, user.Username); err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user.PasswordHash = string(hash)
+	user.PasswordChangedAt = &now
+	user.SessionsRevokedAt = &now
+	return s.repo.Update(context.Background(), user)
+}
+
+// SetRole changes the role of the user. Access tokens carry the role, so the
+// ones issued before the change are revoked and the user picks up the new
+// role on the next refresh.
+func (s *UserService) SetRole(username string, role models.Role) (*models.User, error) {
+	if !role.Valid() {
+		return nil, ErrInvalidRole
+	}
+
+	user, err := s.repo.FindByUsername(context.Background(), normalizeUsername(username))
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	now := time.Now()
+	user.Role = role
+	user.SessionsRevokedAt = &now
+	if err := s.repo.Update(context.Background(), user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// EnsureBootstrapAdmin creates the first admin user, with the owner role,
+// when no users exist yet.
+func (s *UserService) EnsureBootstrapAdmin(username, password, displayName, email string) error {
+	count, err := s.repo.Count(context.Background())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if password == "" {
+		log.Println("No users found and ADMIN_PASSWORD is not set; skipping admin bootstrap")
+		return nil
+	}
+
+	if _, err := s.CreateUser(username, password, displayName, email, models.RoleOwner); err != nil {
+		return err
+	}
+
+	log.Printf("Created bootstrap admin user %q", normalizeUsername(username))
+	return nil
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/store"
+)
+
+func TestUserServiceCreateUser(t *testing.T) {
+	service := NewUserService(store.NewMemoryStore().Users)
+
+	user, err := service.CreateUser(" Alice ", "Sup3r-Secret-Pass!", "", "Alice@Example.com", models.RoleEditor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Username != "alice" || user.DisplayName != "alice" || user.Email != "alice@example.com" || user.Role != models.RoleEditor {
+		t.Errorf("CreateUser = %+v", user)
+	}
+	if _, err := service.Authenticate("alice", "Sup3r-Secret-Pass!"); err != nil {
+		t.Errorf("Authenticate: %v", err)
+	}
+
+	if _, err := service.CreateUser("ALICE", "Sup3r-Secret-Pass!", "", "", models.RoleViewer); !errors.Is(err, ErrUsernameTaken) {
+		t.Errorf("CreateUser with a taken username: %v, want ErrUsernameTaken", err)
+	}
+	if _, err := service.CreateUser("bob", "Sup3r-Secret-Pass!", "", "", "admin"); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("CreateUser with an unknown role: %v, want ErrInvalidRole", err)
+	}
+}
+
+func TestUserServiceSetRole(t *testing.T) {
+	service := NewUserService(store.NewMemoryStore().Users)
+	if _, err := service.CreateUser("alice", "Sup3r-Secret-Pass!", "", "", models.RoleViewer); err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now()
+	user, err := service.SetRole("Alice", models.RoleEditor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := service.GetUserByUsername("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Role != models.RoleEditor || stored.Role != models.RoleEditor {
+		t.Errorf("role = %s, stored %s; want editor", user.Role, stored.Role)
+	}
+	if stored.SessionsRevokedAt == nil || stored.SessionsRevokedAt.Before(before) {
+		t.Errorf("SessionsRevokedAt = %v, want the tokens with the old role revoked", stored.SessionsRevokedAt)
+	}
+
+	if _, err := service.SetRole("alice", "admin"); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("SetRole with an unknown role: %v, want ErrInvalidRole", err)
+	}
+	if _, err := service.SetRole("bob", models.RoleOwner); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("SetRole of an unknown user: %v, want store.ErrNotFound", err)
+	}
+}
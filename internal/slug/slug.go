@@ -0,0 +1,91 @@
+// Package slug turns titles into URL path segments.
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the longest slug Make produces and Valid accepts.
+const MaxLength = 80
+
+// Make returns the slug for s: lowercase ASCII letters and digits separated
+// by single hyphens. Non-ASCII letters are transliterated where possible and
+// dropped otherwise. The result is empty when s has nothing to keep.
+func Make(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range norm.NFKD.String(s) {
+		var out string
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			out = string(r)
+		case r >= 'A' && r <= 'Z':
+			out = string(unicode.ToLower(r))
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks left by decomposing accented letters.
+			continue
+		case r == '\'' || r == '’':
+			// Apostrophes join words: "don't" becomes "dont".
+			continue
+		default:
+			spelling, ok := transliterate(r)
+			if ok && spelling == "" {
+				continue
+			}
+			out = spelling
+		}
+
+		if out == "" {
+			hyphen = b.Len() > 0
+			continue
+		}
+		if hyphen {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteString(out)
+	}
+
+	return truncate(b.String(), MaxLength)
+}
+
+// WithSuffix appends -n to slug, shortening it so the result stays within
+// MaxLength.
+func WithSuffix(slug string, n int) string {
+	suffix := "-" + strconv.Itoa(n)
+	return truncate(slug, MaxLength-len(suffix)) + suffix
+}
+
+// Valid reports whether s is a slug as Make would produce it.
+func Valid(s string) bool {
+	if s == "" || len(s) > MaxLength || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case c == '-' && s[i-1] != '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// truncate shortens slug to at most n bytes, cutting at a hyphen when there
+// is one so words stay whole.
+func truncate(slug string, n int) string {
+	if len(slug) <= n {
+		return slug
+	}
+	slug = slug[:n]
+	if i := strings.LastIndexByte(slug, '-'); i > 0 {
+		slug = slug[:i]
+	}
+	return strings.TrimRight(slug, "-")
+}
@@ -0,0 +1,70 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"  --Portfolio   Backend--  ", "portfolio-backend"},
+		{"Go 1.24 release", "go-1-24-release"},
+		{"don't stop", "dont-stop"},
+		{"Rock ’n’ Roll", "rock-n-roll"},
+		{"Crème Brûlée", "creme-brulee"},
+		{"Ångström Łódź", "angstrom-lodz"},
+		{"Straße", "strasse"},
+		{"Æsir Œuvre Ørsted", "aesir-oeuvre-orsted"},
+		{"Привет мир", "privet-mir"},
+		{"Объявление", "obyavlenie"},
+		{"Ελληνικά", "ellinika"},
+		{"Rock & Roll @ home + more", "rock-and-roll-at-home-plus-more"},
+		{"ｆｕｌｌｗｉｄｔｈ", "fullwidth"},
+		{"日本語 app", "app"},
+		{"日本語", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Make(tt.in); got != tt.want {
+			t.Errorf("Make(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMakeTruncatesAtWords(t *testing.T) {
+	got := Make(strings.Repeat("word ", 30))
+	if len(got) > MaxLength || strings.HasSuffix(got, "-") || !strings.HasSuffix(got, "word") {
+		t.Errorf("Make truncated to %q", got)
+	}
+	if !Valid(got) {
+		t.Errorf("Make produced invalid slug %q", got)
+	}
+}
+
+func TestWithSuffix(t *testing.T) {
+	if got := WithSuffix("my-project", 2); got != "my-project-2" {
+		t.Errorf("WithSuffix = %q, want my-project-2", got)
+	}
+
+	long := Make(strings.Repeat("word ", 30))
+	got := WithSuffix(long, 12)
+	if len(got) > MaxLength || !strings.HasSuffix(got, "word-12") || !Valid(got) {
+		t.Errorf("WithSuffix(%q, 12) = %q", long, got)
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, s := range []string{"a", "my-project", "go-1-24", strings.Repeat("a", MaxLength)} {
+		if !Valid(s) {
+			t.Errorf("Valid(%q) = false", s)
+		}
+	}
+	for _, s := range []string{"", "-a", "a-", "a--b", "My-Project", "a_b", "a b", "crème", strings.Repeat("a", MaxLength+1)} {
+		if Valid(s) {
+			t.Errorf("Valid(%q) = true", s)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package slug
+
+import "strings"
+
+// translit maps letters that do not decompose into ASCII letters plus
+// combining marks. Keys are lowercase; uppercase letters are lowered first.
+var translit = map[rune]string{
+	// Latin
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'å': "a", 'ł': "l", 'đ': "d",
+	'ð': "d", 'þ': "th", 'ı': "i", 'ħ': "h", 'ŀ': "l", 'ŋ': "ng", 'ſ': "s",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u", 'ђ': "dj", 'ј': "j",
+	'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz",
+
+	// Symbols that read as words
+	'&': "and", '@': "at", '+': "plus",
+}
+
+// transliterate returns the ASCII spelling of r. ok is false when r has none
+// and should separate words; letters such as the Cyrillic hard sign are
+// silent and have an empty spelling.
+func transliterate(r rune) (spelling string, ok bool) {
+	lower := []rune(strings.ToLower(string(r)))
+	if len(lower) != 1 {
+		return "", false
+	}
+	spelling, ok = translit[lower[0]]
+	return spelling, ok
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// ObjectClient is the part of an S3-compatible client that ObjectStorage
+// needs. A thin adapter over an S3, MinIO or GCS client satisfies it.
+// GetObject must return ErrNotFound for missing objects, and DeleteObject
+// must succeed for them, as S3 does.
+type ObjectClient interface {
+	PutObject(ctx context.Context, bucket, key string, body io.Reader, size int64, contentType string) error
+	GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error)
+	DeleteObject(ctx context.Context, bucket, key string) error
+}
+
+// ObjectStorage keeps files as objects in a bucket of an S3-compatible
+// object store.
+type ObjectStorage struct {
+	client ObjectClient
+	bucket string
+}
+
+func NewObjectStorage(client ObjectClient, bucket string) *ObjectStorage {
+	return &ObjectStorage{
+		client: client,
+		bucket: bucket,
+	}
+}
+
+func (s *ObjectStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	return s.client.PutObject(ctx, s.bucket, key, r, size, contentType)
+}
+
+func (s *ObjectStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	return s.client.GetObject(ctx, s.bucket, key)
+}
+
+func (s *ObjectStorage) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	return s.client.DeleteObject(ctx, s.bucket, key)
+}
+
+// MemoryObjectClient is an in-memory stand-in for an S3-compatible object
+// store. It is intended for development and tests; objects are lost on
+// restart.
+type MemoryObjectClient struct {
+	objects map[string][]byte
+	mutex   sync.RWMutex
+}
+
+func NewMemoryObjectClient() *MemoryObjectClient {
+	return &MemoryObjectClient{
+		objects: make(map[string][]byte),
+	}
+}
+
+func (c *MemoryObjectClient) PutObject(ctx context.Context, bucket, key string, body io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.objects[bucket+"/"+key] = data
+	return nil
+}
+
+func (c *MemoryObjectClient) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	data, ok := c.objects[bucket+"/"+key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (c *MemoryObjectClient) DeleteObject(ctx context.Context, bucket, key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.objects, bucket+"/"+key)
+	return nil
+}
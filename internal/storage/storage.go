@@ -0,0 +1,59 @@
+// Package storage stores uploaded files. Files are addressed by keys, which
+// are slash-separated relative paths chosen by the caller.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Supported values for configs.Config.MediaStorage.
+const (
+	BackendLocal  = "local"
+	BackendMemory = "memory"
+)
+
+var (
+	// ErrNotFound is returned when no file is stored under a key.
+	ErrNotFound = errors.New("file not found")
+	// ErrInvalidKey is returned for keys that are empty, absolute or escape
+	// the storage root.
+	ErrInvalidKey = errors.New("invalid storage key")
+)
+
+// Storage is a place to keep files.
+type Storage interface {
+	// Put stores size bytes from r under key, replacing any file there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns the file stored under key.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the file under key. Deleting a missing file is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the storage selected by backend: BackendLocal keeps files
+// under dir, BackendMemory keeps them in an in-memory object store.
+func New(backend, dir string) (Storage, error) {
+	switch backend {
+	case BackendLocal, "":
+		return NewLocalStorage(dir)
+	case BackendMemory:
+		return NewObjectStorage(NewMemoryObjectClient(), "media"), nil
+	default:
+		return nil, fmt.Errorf("unknown media storage %q", backend)
+	}
+}
+
+// checkKey rejects keys that are not clean relative paths.
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+)
+
+// MemoryAPIKeyRepository keeps API keys in process memory. It is intended
+// for development and tests; data is lost on restart.
+type MemoryAPIKeyRepository struct {
+	keys  map[primitive.ObjectID]models.APIKey
+	mutex sync.RWMutex
+}
+
+func NewMemoryAPIKeyRepository() *MemoryAPIKeyRepository {
+	return &MemoryAPIKeyRepository{
+		keys: make(map[primitive.ObjectID]models.APIKey),
+	}
+}
+
+func (r *MemoryAPIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existing := range r.keys {
+		if existing.Prefix == key.Prefix {
+			return ErrDuplicate
+		}
+	}
+
+	if key.ID.IsZero() {
+		key.ID = primitive.NewObjectID()
+	}
+	r.keys[key.ID] = cloneAPIKey(*key)
+	return nil
+}
+
+func (r *MemoryAPIKeyRepository) FindAll(ctx context.Context) ([]models.APIKey, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	keys := make([]models.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, cloneAPIKey(key))
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+
+	return keys, nil
+}
+
+func (r *MemoryAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, key := range r.keys {
+		if key.Prefix == prefix {
+			key = cloneAPIKey(key)
+			return &key, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryAPIKeyRepository) Revoke(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok || key.RevokedAt != nil {
+		return ErrNotFound
+	}
+	key.RevokedAt = &at
+	r.keys[id] = key
+	return nil
+}
+
+func (r *MemoryAPIKeyRepository) UpdateLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return ErrNotFound
+	}
+	key.LastUsedAt = &at
+	r.keys[id] = key
+	return nil
+}
+
+// cloneAPIKey copies the slice fields so callers cannot mutate stored data.
+func cloneAPIKey(key models.APIKey) models.APIKey {
+	if key.Scopes != nil {
+		key.Scopes = append([]models.Permission(nil), key.Scopes...)
+	}
+	return key
+}
@@ -0,0 +1,211 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/search"
+)
+
+// MemoryContactRepository keeps contacts in process memory. It is intended
+// for development and tests; data is lost on restart.
+type MemoryContactRepository struct {
+	contacts map[primitive.ObjectID]models.Contact
+	index    *search.Index
+	mutex    sync.RWMutex
+}
+
+func NewMemoryContactRepository() *MemoryContactRepository {
+	return &MemoryContactRepository{
+		contacts: make(map[primitive.ObjectID]models.Contact),
+		index:    search.NewIndex(),
+	}
+}
+
+func (r *MemoryContactRepository) Create(ctx context.Context, contact *models.Contact) error {
+	if contact.ID.IsZero() {
+		contact.ID = primitive.NewObjectID()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.contacts[contact.ID] = *contact
+	r.index.Put(contact.ID.Hex(), contactSearchFields(contact)...)
+	return nil
+}
+
+func (r *MemoryContactRepository) Find(ctx context.Context, query ContactQuery) ([]models.Contact, error) {
+	contacts := r.filter(query)
+	sortBy := contactSortField(query.SortBy)
+
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return compareKeys(
+			ContactSortValue(&contacts[i], sortBy), contacts[i].ID,
+			ContactSortValue(&contacts[j], sortBy), contacts[j].ID,
+			query.SortAsc,
+		) < 0
+	})
+
+	start := 0
+	if query.After != nil {
+		value, err := parseCursorValue(query.After, ContactSortValue(&models.Contact{}, sortBy))
+		if err != nil {
+			return nil, err
+		}
+		start = sort.Search(len(contacts), func(i int) bool {
+			return compareKeys(ContactSortValue(&contacts[i], sortBy), contacts[i].ID, value, query.After.ID, query.SortAsc) > 0
+		})
+	} else {
+		start = int(min(query.Skip, int64(len(contacts))))
+	}
+
+	return paginate(contacts, start, query.Limit), nil
+}
+
+func (r *MemoryContactRepository) Count(ctx context.Context, query ContactQuery) (int64, error) {
+	return int64(len(r.filter(query))), nil
+}
+
+func (r *MemoryContactRepository) Search(ctx context.Context, text string, limit int64) ([]ScoredContact, error) {
+	hits := r.index.Search(text, int(limit))
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	results := make([]ScoredContact, 0, len(hits))
+	for _, hit := range hits {
+		id, _ := primitive.ObjectIDFromHex(hit.ID)
+		if contact, ok := r.contacts[id]; ok {
+			results = append(results, ScoredContact{Contact: contact, Score: hit.Score})
+		}
+	}
+	return results, nil
+}
+
+func (r *MemoryContactRepository) filter(query ContactQuery) []models.Contact {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	contacts := make([]models.Contact, 0, len(r.contacts))
+	for _, contact := range r.contacts {
+		switch {
+		case (contact.DeletedAt != nil) != query.Trashed,
+			query.Read != nil && contact.Read != *query.Read,
+			!inTimeRange(contact.CreatedAt, query.Since, query.Until),
+			query.Email != "" && !containsFold(contact.Email, query.Email),
+			query.Subject != "" && !containsFold(contact.Subject, query.Subject):
+			continue
+		}
+		contacts = append(contacts, contact)
+	}
+	return contacts
+}
+
+func (r *MemoryContactRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Contact, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	contact, ok := r.contacts[id]
+	if !ok || contact.DeletedAt != nil {
+		return nil, ErrNotFound
+	}
+	return &contact, nil
+}
+
+func (r *MemoryContactRepository) MarkAsRead(ctx context.Context, id primitive.ObjectID, version int64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	contact, ok := r.contacts[id]
+	if !ok || contact.DeletedAt != nil {
+		return ErrNotFound
+	}
+	if contact.Version != version {
+		return ErrVersionConflict
+	}
+	contact.Read = true
+	contact.Version++
+	r.contacts[id] = contact
+	return nil
+}
+
+func (r *MemoryContactRepository) Trash(ctx context.Context, id primitive.ObjectID, version int64, deletedAt time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	contact, ok := r.contacts[id]
+	if !ok || contact.DeletedAt != nil {
+		return ErrNotFound
+	}
+	if contact.Version != version {
+		return ErrVersionConflict
+	}
+	contact.DeletedAt = &deletedAt
+	contact.Version++
+	r.contacts[id] = contact
+	r.index.Remove(id.Hex())
+	return nil
+}
+
+func (r *MemoryContactRepository) FindTrashedByID(ctx context.Context, id primitive.ObjectID) (*models.Contact, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	contact, ok := r.contacts[id]
+	if !ok || contact.DeletedAt == nil {
+		return nil, ErrNotFound
+	}
+	return &contact, nil
+}
+
+func (r *MemoryContactRepository) Restore(ctx context.Context, id primitive.ObjectID, version int64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	contact, ok := r.contacts[id]
+	if !ok || contact.DeletedAt == nil {
+		return ErrNotFound
+	}
+	if contact.Version != version {
+		return ErrVersionConflict
+	}
+	contact.DeletedAt = nil
+	contact.Version++
+	r.contacts[id] = contact
+	r.index.Put(id.Hex(), contactSearchFields(&contact)...)
+	return nil
+}
+
+func (r *MemoryContactRepository) Purge(ctx context.Context, id primitive.ObjectID, version int64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	contact, ok := r.contacts[id]
+	if !ok || contact.DeletedAt == nil {
+		return ErrNotFound
+	}
+	if contact.Version != version {
+		return ErrVersionConflict
+	}
+	delete(r.contacts, id)
+	return nil
+}
+
+func (r *MemoryContactRepository) PurgeTrashed(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var purged int64
+	for id, contact := range r.contacts {
+		if contact.DeletedAt != nil && contact.DeletedAt.Before(cutoff) {
+			delete(r.contacts, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+)
+
+// MemoryLoginEventRepository keeps the login audit trail in process memory.
+// It is intended for development and tests; data is lost on restart.
+type MemoryLoginEventRepository struct {
+	events []models.LoginEvent
+	mutex  sync.RWMutex
+}
+
+func NewMemoryLoginEventRepository() *MemoryLoginEventRepository {
+	return &MemoryLoginEventRepository{}
+}
+
+func (r *MemoryLoginEventRepository) Create(ctx context.Context, event *models.LoginEvent) error {
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *MemoryLoginEventRepository) Find(ctx context.Context, filter LoginEventFilter) ([]models.LoginEvent, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	// Events are appended in time order, so walk backwards for newest first
+	events := []models.LoginEvent{}
+	for i := len(r.events) - 1; i >= 0; i-- {
+		event := r.events[i]
+		switch {
+		case filter.Username != "" && event.Username != filter.Username,
+			filter.IP != "" && event.IP != filter.IP,
+			filter.Success != nil && event.Success != *filter.Success,
+			!filter.Since.IsZero() && event.CreatedAt.Before(filter.Since),
+			!filter.Until.IsZero() && event.CreatedAt.After(filter.Until):
+			continue
+		}
+
+		events = append(events, event)
+		if filter.Limit > 0 && int64(len(events)) >= filter.Limit {
+			break
+		}
+	}
+
+	return events, nil
+}
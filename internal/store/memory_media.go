@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+)
+
+// MemoryMediaRepository keeps media metadata in process memory. It is
+// intended for development and tests; data is lost on restart.
+type MemoryMediaRepository struct {
+	media map[primitive.ObjectID]models.Media
+	mutex sync.RWMutex
+}
+
+func NewMemoryMediaRepository() *MemoryMediaRepository {
+	return &MemoryMediaRepository{
+		media: make(map[primitive.ObjectID]models.Media),
+	}
+}
+
+func (r *MemoryMediaRepository) Create(ctx context.Context, media *models.Media) error {
+	if media.ID.IsZero() {
+		media.ID = primitive.NewObjectID()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.media[media.ID]; ok {
+		return ErrDuplicate
+	}
+	r.media[media.ID] = cloneMedia(*media)
+	return nil
+}
+
+func (r *MemoryMediaRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Media, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	media, ok := r.media[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	clone := cloneMedia(media)
+	return &clone, nil
+}
+
+func (r *MemoryMediaRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Media, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var found []models.Media
+	for _, id := range ids {
+		if media, ok := r.media[id]; ok {
+			found = append(found, cloneMedia(media))
+		}
+	}
+	return found, nil
+}
+
+func (r *MemoryMediaRepository) Find(ctx context.Context, query MediaQuery) ([]models.Media, error) {
+	r.mutex.RLock()
+	all := make([]models.Media, 0, len(r.media))
+	for _, media := range r.media {
+		all = append(all, cloneMedia(media))
+	}
+	r.mutex.RUnlock()
+
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].CreatedAt.Equal(all[j].CreatedAt) {
+			return all[i].CreatedAt.After(all[j].CreatedAt)
+		}
+		return all[i].ID.Hex() > all[j].ID.Hex()
+	})
+
+	start := int(min(query.Skip, int64(len(all))))
+	return paginate(all, start, query.Limit), nil
+}
+
+func (r *MemoryMediaRepository) Count(ctx context.Context) (int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return int64(len(r.media)), nil
+}
+
+func (r *MemoryMediaRepository) Update(ctx context.Context, media *models.Media) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.media[media.ID]; !ok {
+		return ErrNotFound
+	}
+	r.media[media.ID] = cloneMedia(*media)
+	return nil
+}
+
+func (r *MemoryMediaRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.media[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.media, id)
+	return nil
+}
+
+func cloneMedia(media models.Media) models.Media {
+	if media.Variants != nil {
+		media.Variants = append([]models.MediaVariant(nil), media.Variants...)
+	}
+	return media
+}
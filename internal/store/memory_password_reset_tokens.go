@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+)
+
+// MemoryPasswordResetTokenRepository keeps reset tokens in process memory. It
+// is intended for development and tests; data is lost on restart.
+type MemoryPasswordResetTokenRepository struct {
+	tokens map[primitive.ObjectID]models.PasswordResetToken
+	mutex  sync.RWMutex
+}
+
+func NewMemoryPasswordResetTokenRepository() *MemoryPasswordResetTokenRepository {
+	return &MemoryPasswordResetTokenRepository{
+		tokens: make(map[primitive.ObjectID]models.PasswordResetToken),
+	}
+}
+
+func (r *MemoryPasswordResetTokenRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.tokens[token.ID] = *token
+	return nil
+}
+
+func (r *MemoryPasswordResetTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryPasswordResetTokenRepository) MarkUsed(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	token, ok := r.tokens[id]
+	if !ok || token.UsedAt != nil {
+		return false, nil
+	}
+	token.UsedAt = &at
+	r.tokens[id] = token
+	return true, nil
+}
+
+func (r *MemoryPasswordResetTokenRepository) InvalidateForUser(ctx context.Context, username string, at time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for id, token := range r.tokens {
+		if token.Username == username && token.UsedAt == nil {
+			token.UsedAt = &at
+			r.tokens[id] = token
+		}
+	}
+	return nil
+}
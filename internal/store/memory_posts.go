@@ -0,0 +1,211 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+)
+
+// MemoryPostRepository keeps posts in process memory. It is intended for
+// development and tests; data is lost on restart.
+type MemoryPostRepository struct {
+	posts map[primitive.ObjectID]models.Post
+	mutex sync.RWMutex
+}
+
+func NewMemoryPostRepository() *MemoryPostRepository {
+	return &MemoryPostRepository{
+		posts: make(map[primitive.ObjectID]models.Post),
+	}
+}
+
+func (r *MemoryPostRepository) Create(ctx context.Context, post *models.Post) error {
+	if post.ID.IsZero() {
+		post.ID = primitive.NewObjectID()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.slugTaken(post.Slug, post.ID) {
+		return ErrDuplicate
+	}
+	r.posts[post.ID] = clonePost(*post)
+	return nil
+}
+
+func (r *MemoryPostRepository) Find(ctx context.Context, query PostQuery) ([]models.Post, error) {
+	posts := r.find(func(p models.Post) bool { return matchPost(&p, query) })
+
+	sortBy := postSortField(query)
+	sort.Slice(posts, func(i, j int) bool {
+		return compareKeys(
+			postSortValue(&posts[i], sortBy), posts[i].ID,
+			postSortValue(&posts[j], sortBy), posts[j].ID,
+			false,
+		) < 0
+	})
+
+	start := int(min(query.Skip, int64(len(posts))))
+	return paginate(posts, start, query.Limit), nil
+}
+
+func (r *MemoryPostRepository) Count(ctx context.Context, query PostQuery) (int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var count int64
+	for _, post := range r.posts {
+		if matchPost(&post, query) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryPostRepository) Tags(ctx context.Context, query PostQuery) ([]models.FacetCount, error) {
+	r.mutex.RLock()
+	counts := make(map[string]int64)
+	for _, post := range r.posts {
+		if !matchPost(&post, query) {
+			continue
+		}
+		for _, tag := range post.Tags {
+			counts[tag]++
+		}
+	}
+	r.mutex.RUnlock()
+
+	return facetCounts(counts), nil
+}
+
+func matchPost(post *models.Post, query PostQuery) bool {
+	return (query.Tag == "" || slices.Contains(post.Tags, query.Tag)) &&
+		(query.PublicAt.IsZero() || post.IsPublic(query.PublicAt))
+}
+
+// postSortValue returns the value of post's sort field; drafts sort as the
+// zero time, last, like missing fields in MongoDB.
+func postSortValue(post *models.Post, field string) interface{} {
+	if field == "published_at" {
+		if post.PublishedAt == nil {
+			return time.Time{}
+		}
+		return *post.PublishedAt
+	}
+	return post.CreatedAt
+}
+
+func (r *MemoryPostRepository) find(match func(models.Post) bool) []models.Post {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	posts := make([]models.Post, 0, len(r.posts))
+	for _, post := range r.posts {
+		if match(post) {
+			posts = append(posts, clonePost(post))
+		}
+	}
+	return posts
+}
+
+func (r *MemoryPostRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Post, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	post, ok := r.posts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	post = clonePost(post)
+	return &post, nil
+}
+
+func (r *MemoryPostRepository) FindBySlug(ctx context.Context, slug string) (*models.Post, error) {
+	posts := r.find(func(p models.Post) bool { return p.Slug == slug })
+	if len(posts) == 0 {
+		return nil, ErrNotFound
+	}
+	return &posts[0], nil
+}
+
+func (r *MemoryPostRepository) SlugTaken(ctx context.Context, slug string, except primitive.ObjectID) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.slugTaken(slug, except), nil
+}
+
+// slugTaken is SlugTaken for callers holding the mutex.
+func (r *MemoryPostRepository) slugTaken(slug string, except primitive.ObjectID) bool {
+	for id, post := range r.posts {
+		if id != except && post.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryPostRepository) UsesMedia(ctx context.Context, mediaID primitive.ObjectID) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, post := range r.posts {
+		if post.CoverMediaID != nil && *post.CoverMediaID == mediaID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryPostRepository) Replace(ctx context.Context, post *models.Post) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, ok := r.posts[post.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if stored.Version != post.Version {
+		return ErrVersionConflict
+	}
+	if r.slugTaken(post.Slug, post.ID) {
+		return ErrDuplicate
+	}
+	post.Version++
+	r.posts[post.ID] = clonePost(*post)
+	return nil
+}
+
+func (r *MemoryPostRepository) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, ok := r.posts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if stored.Version != version {
+		return ErrVersionConflict
+	}
+	delete(r.posts, id)
+	return nil
+}
+
+// clonePost copies the slice and pointer fields so callers cannot mutate
+// stored data.
+func clonePost(post models.Post) models.Post {
+	if post.Tags != nil {
+		post.Tags = append([]string(nil), post.Tags...)
+	}
+	if post.CoverMediaID != nil {
+		id := *post.CoverMediaID
+		post.CoverMediaID = &id
+	}
+	post.PublishedAt = cloneTime(post.PublishedAt)
+	return post
+}
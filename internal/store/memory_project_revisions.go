@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+)
+
+// MemoryProjectRevisionRepository keeps project revisions in process memory.
+// It is intended for development and tests; data is lost on restart.
+type MemoryProjectRevisionRepository struct {
+	revisions map[primitive.ObjectID][]models.ProjectRevision // by project, oldest first
+	mutex     sync.RWMutex
+}
+
+func NewMemoryProjectRevisionRepository() *MemoryProjectRevisionRepository {
+	return &MemoryProjectRevisionRepository{
+		revisions: make(map[primitive.ObjectID][]models.ProjectRevision),
+	}
+}
+
+func (r *MemoryProjectRevisionRepository) Create(ctx context.Context, revision *models.ProjectRevision) error {
+	if revision.ID.IsZero() {
+		revision.ID = primitive.NewObjectID()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existing := range r.revisions[revision.ProjectID] {
+		if existing.Number == revision.Number {
+			return ErrDuplicate
+		}
+	}
+	stored := *revision
+	stored.Snapshot = cloneProject(revision.Snapshot)
+	r.revisions[revision.ProjectID] = append(r.revisions[revision.ProjectID], stored)
+	return nil
+}
+
+func (r *MemoryProjectRevisionRepository) FindByProject(ctx context.Context, projectID primitive.ObjectID) ([]models.ProjectRevision, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	stored := r.revisions[projectID]
+	revisions := make([]models.ProjectRevision, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		revision := stored[i]
+		revision.Snapshot = cloneProject(revision.Snapshot)
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+func (r *MemoryProjectRevisionRepository) FindByNumber(ctx context.Context, projectID primitive.ObjectID, number int64) (*models.ProjectRevision, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, revision := range r.revisions[projectID] {
+		if revision.Number == number {
+			revision.Snapshot = cloneProject(revision.Snapshot)
+			return &revision, nil
+		}
+	}
+	return nil, ErrNotFound
+}
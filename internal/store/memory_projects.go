@@ -0,0 +1,402 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/search"
+)
+
+// MemoryProjectRepository keeps projects in process memory. It is intended
+// for development and tests; data is lost on restart.
+type MemoryProjectRepository struct {
+	projects map[primitive.ObjectID]models.Project
+	index    *search.Index
+	mutex    sync.RWMutex
+}
+
+func NewMemoryProjectRepository() *MemoryProjectRepository {
+	return &MemoryProjectRepository{
+		projects: make(map[primitive.ObjectID]models.Project),
+		index:    search.NewIndex(),
+	}
+}
+
+func (r *MemoryProjectRepository) Create(ctx context.Context, project *models.Project) error {
+	if project.ID.IsZero() {
+		project.ID = primitive.NewObjectID()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.slugTaken(project.Slug, project.ID) {
+		return ErrDuplicate
+	}
+	r.projects[project.ID] = cloneProject(*project)
+	r.index.Put(project.ID.Hex(), projectSearchFields(project)...)
+	return nil
+}
+
+func (r *MemoryProjectRepository) Find(ctx context.Context, query ProjectQuery) ([]models.Project, error) {
+	projects := r.find(func(p models.Project) bool { return matchProject(&p, query) })
+	sortBy := projectSortField(query.SortBy)
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		return compareKeys(
+			ProjectSortValue(&projects[i], sortBy), projects[i].ID,
+			ProjectSortValue(&projects[j], sortBy), projects[j].ID,
+			query.SortAsc,
+		) < 0
+	})
+
+	start := 0
+	if query.After != nil {
+		value, err := parseCursorValue(query.After, ProjectSortValue(&models.Project{}, sortBy))
+		if err != nil {
+			return nil, err
+		}
+		start = sort.Search(len(projects), func(i int) bool {
+			return compareKeys(ProjectSortValue(&projects[i], sortBy), projects[i].ID, value, query.After.ID, query.SortAsc) > 0
+		})
+	} else {
+		start = int(min(query.Skip, int64(len(projects))))
+	}
+
+	return paginate(projects, start, query.Limit), nil
+}
+
+func (r *MemoryProjectRepository) Count(ctx context.Context, query ProjectQuery) (int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var count int64
+	for _, project := range r.projects {
+		if matchProject(&project, query) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryProjectRepository) Facets(ctx context.Context, query ProjectQuery) (*models.ProjectFacets, error) {
+	r.mutex.RLock()
+	categories := make(map[string]int64)
+	technologies := make(map[string]int64)
+	for _, project := range r.projects {
+		if !matchProject(&project, query) {
+			continue
+		}
+		if project.Category != "" {
+			categories[project.Category]++
+		}
+		for _, tech := range project.Technologies {
+			if tech != "" {
+				technologies[tech]++
+			}
+		}
+	}
+	r.mutex.RUnlock()
+
+	return &models.ProjectFacets{
+		Categories:   facetCounts(categories),
+		Technologies: facetCounts(technologies),
+	}, nil
+}
+
+func (r *MemoryProjectRepository) Search(ctx context.Context, text string, query ProjectQuery) ([]ScoredProject, error) {
+	// Filter before limiting, as the Mongo query does.
+	hits := r.index.Search(text, 0)
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	results := make([]ScoredProject, 0, len(hits))
+	for _, hit := range hits {
+		id, _ := primitive.ObjectIDFromHex(hit.ID)
+		if project, ok := r.projects[id]; ok && matchProject(&project, query) {
+			results = append(results, ScoredProject{Project: cloneProject(project), Score: hit.Score})
+		}
+	}
+	return paginate(results, 0, query.Limit), nil
+}
+
+func matchProject(project *models.Project, query ProjectQuery) bool {
+	switch {
+	case (project.DeletedAt != nil) != query.Trashed,
+		query.Category != "" && project.Category != query.Category,
+		query.Featured != nil && project.Featured != *query.Featured,
+		query.Status != "" && project.Status != query.Status,
+		!query.PublicAt.IsZero() && !project.IsPublic(query.PublicAt),
+		!query.PublishDue.IsZero() && (project.PublishAt == nil || project.PublishAt.After(query.PublishDue)),
+		!query.UnpublishDue.IsZero() && (project.UnpublishAt == nil || project.UnpublishAt.After(query.UnpublishDue)),
+		!inTimeRange(project.CreatedAt, query.CreatedSince, query.CreatedUntil),
+		!inTimeRange(project.UpdatedAt, query.UpdatedSince, query.UpdatedUntil):
+		return false
+	}
+	if len(query.Technologies) == 0 {
+		return true
+	}
+
+	for _, tech := range query.Technologies {
+		has := slices.Contains(project.Technologies, tech)
+		if has && !query.AllTechnologies {
+			return true
+		}
+		if !has && query.AllTechnologies {
+			return false
+		}
+	}
+	return query.AllTechnologies
+}
+
+// facetCounts orders facet values by count, then value, like the Mongo
+// aggregation.
+func facetCounts(counts map[string]int64) []models.FacetCount {
+	facets := make([]models.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, models.FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+	return facets
+}
+
+func (r *MemoryProjectRepository) find(match func(models.Project) bool) []models.Project {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	projects := make([]models.Project, 0, len(r.projects))
+	for _, project := range r.projects {
+		if match(project) {
+			projects = append(projects, cloneProject(project))
+		}
+	}
+
+	return projects
+}
+
+func (r *MemoryProjectRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Project, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	project, ok := r.projects[id]
+	if !ok || project.DeletedAt != nil {
+		return nil, ErrNotFound
+	}
+	project = cloneProject(project)
+	return &project, nil
+}
+
+func (r *MemoryProjectRepository) FindBySlug(ctx context.Context, slug string) (*models.Project, error) {
+	projects := r.find(func(p models.Project) bool { return p.DeletedAt == nil && p.Slug == slug })
+	if len(projects) == 0 {
+		return nil, ErrNotFound
+	}
+	return &projects[0], nil
+}
+
+func (r *MemoryProjectRepository) FindByPreviousSlug(ctx context.Context, slug string) (*models.Project, error) {
+	projects := r.find(func(p models.Project) bool {
+		return p.DeletedAt == nil && slices.Contains(p.PreviousSlugs, slug)
+	})
+	if len(projects) == 0 {
+		return nil, ErrNotFound
+	}
+
+	latest := &projects[0]
+	for i := range projects {
+		if projects[i].UpdatedAt.After(latest.UpdatedAt) {
+			latest = &projects[i]
+		}
+	}
+	return latest, nil
+}
+
+func (r *MemoryProjectRepository) SlugTaken(ctx context.Context, slug string, except primitive.ObjectID) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.slugTaken(slug, except), nil
+}
+
+// slugTaken is SlugTaken for callers holding the mutex. Empty slugs are
+// never taken, like the sparse unique index in MongoDB.
+func (r *MemoryProjectRepository) slugTaken(slug string, except primitive.ObjectID) bool {
+	if slug == "" {
+		return false
+	}
+	for id, project := range r.projects {
+		if id != except && project.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryProjectRepository) UsesMedia(ctx context.Context, mediaID primitive.ObjectID) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, project := range r.projects {
+		if slices.Contains(project.MediaIDs, mediaID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryProjectRepository) Replace(ctx context.Context, project *models.Project) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, ok := r.projects[project.ID]
+	if !ok || stored.DeletedAt != nil {
+		return ErrNotFound
+	}
+	if stored.Version != project.Version {
+		return ErrVersionConflict
+	}
+	if r.slugTaken(project.Slug, project.ID) {
+		return ErrDuplicate
+	}
+	project.Version++
+	r.projects[project.ID] = cloneProject(*project)
+	r.index.Put(project.ID.Hex(), projectSearchFields(project)...)
+	return nil
+}
+
+func (r *MemoryProjectRepository) ReplaceMany(ctx context.Context, projects []*models.Project) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, project := range projects {
+		stored, ok := r.projects[project.ID]
+		if !ok || stored.DeletedAt != nil {
+			return ErrNotFound
+		}
+		if stored.Version != project.Version {
+			return ErrVersionConflict
+		}
+		if r.slugTaken(project.Slug, project.ID) {
+			return ErrDuplicate
+		}
+	}
+	for _, project := range projects {
+		project.Version++
+		r.projects[project.ID] = cloneProject(*project)
+		r.index.Put(project.ID.Hex(), projectSearchFields(project)...)
+	}
+	return nil
+}
+
+func (r *MemoryProjectRepository) Trash(ctx context.Context, id primitive.ObjectID, version int64, deletedAt time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, ok := r.projects[id]
+	if !ok || stored.DeletedAt != nil {
+		return ErrNotFound
+	}
+	if stored.Version != version {
+		return ErrVersionConflict
+	}
+	stored.DeletedAt = &deletedAt
+	stored.Version++
+	r.projects[id] = stored
+	r.index.Remove(id.Hex())
+	return nil
+}
+
+func (r *MemoryProjectRepository) FindTrashedByID(ctx context.Context, id primitive.ObjectID) (*models.Project, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	project, ok := r.projects[id]
+	if !ok || project.DeletedAt == nil {
+		return nil, ErrNotFound
+	}
+	project = cloneProject(project)
+	return &project, nil
+}
+
+func (r *MemoryProjectRepository) Restore(ctx context.Context, id primitive.ObjectID, version int64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, ok := r.projects[id]
+	if !ok || stored.DeletedAt == nil {
+		return ErrNotFound
+	}
+	if stored.Version != version {
+		return ErrVersionConflict
+	}
+	stored.DeletedAt = nil
+	stored.Version++
+	r.projects[id] = stored
+	r.index.Put(id.Hex(), projectSearchFields(&stored)...)
+	return nil
+}
+
+func (r *MemoryProjectRepository) Purge(ctx context.Context, id primitive.ObjectID, version int64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, ok := r.projects[id]
+	if !ok || stored.DeletedAt == nil {
+		return ErrNotFound
+	}
+	if stored.Version != version {
+		return ErrVersionConflict
+	}
+	delete(r.projects, id)
+	return nil
+}
+
+func (r *MemoryProjectRepository) PurgeTrashed(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var purged int64
+	for id, project := range r.projects {
+		if project.DeletedAt != nil && project.DeletedAt.Before(cutoff) {
+			delete(r.projects, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// cloneProject copies the slice and pointer fields so callers cannot mutate
+// stored data.
+func cloneProject(project models.Project) models.Project {
+	if project.Technologies != nil {
+		project.Technologies = append([]string(nil), project.Technologies...)
+	}
+	if project.MediaIDs != nil {
+		project.MediaIDs = append([]primitive.ObjectID(nil), project.MediaIDs...)
+	}
+	if project.PreviousSlugs != nil {
+		project.PreviousSlugs = append([]string(nil), project.PreviousSlugs...)
+	}
+	project.PublishAt = cloneTime(project.PublishAt)
+	project.UnpublishAt = cloneTime(project.UnpublishAt)
+	project.DeletedAt = cloneTime(project.DeletedAt)
+	return project
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+)
+
+// MemoryRefreshTokenRepository keeps refresh tokens in process memory. It is
+// intended for development and tests; data is lost on restart.
+type MemoryRefreshTokenRepository struct {
+	tokens map[primitive.ObjectID]models.RefreshToken
+	mutex  sync.RWMutex
+}
+
+func NewMemoryRefreshTokenRepository() *MemoryRefreshTokenRepository {
+	return &MemoryRefreshTokenRepository{
+		tokens: make(map[primitive.ObjectID]models.RefreshToken),
+	}
+}
+
+func (r *MemoryRefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.tokens[token.ID] = *token
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryRefreshTokenRepository) MarkRotated(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	token, ok := r.tokens[id]
+	if !ok || token.RotatedAt != nil || token.RevokedAt != nil {
+		return false, nil
+	}
+	token.RotatedAt = &at
+	r.tokens[id] = token
+	return true, nil
+}
+
+func (r *MemoryRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	r.revokeWhere(func(token models.RefreshToken) bool { return token.FamilyID == familyID }, at)
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) RevokeAllForUser(ctx context.Context, username string, at time.Time) error {
+	r.revokeWhere(func(token models.RefreshToken) bool { return token.Username == username }, at)
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) revokeWhere(match func(models.RefreshToken) bool, at time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for id, token := range r.tokens {
+		if token.RevokedAt == nil && match(token) {
+			token.RevokedAt = &at
+			r.tokens[id] = token
+		}
+	}
+}
+
+// MemoryRevokedTokenRepository keeps the access token revocation list in
+// process memory. Entries are dropped once the token would have expired.
+type MemoryRevokedTokenRepository struct {
+	tokens map[string]models.RevokedToken
+	mutex  sync.Mutex
+}
+
+func NewMemoryRevokedTokenRepository() *MemoryRevokedTokenRepository {
+	return &MemoryRevokedTokenRepository{
+		tokens: make(map[string]models.RevokedToken),
+	}
+}
+
+func (r *MemoryRevokedTokenRepository) Add(ctx context.Context, token *models.RevokedToken) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.tokens[token.JTI] = *token
+	return nil
+}
+
+func (r *MemoryRevokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	token, ok := r.tokens[jti]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(token.ExpiresAt) {
+		delete(r.tokens, jti)
+		return false, nil
+	}
+	return true, nil
+}
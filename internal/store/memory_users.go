@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+)
+
+// MemoryUserRepository keeps users in process memory. It is intended
+// for development and tests; data is lost on restart.
+type MemoryUserRepository struct {
+	users map[primitive.ObjectID]models.User
+	mutex sync.RWMutex
+}
+
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{
+		users: make(map[primitive.ObjectID]models.User),
+	}
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existing := range r.users {
+		if existing.Username == user.Username {
+			return ErrDuplicate
+		}
+	}
+
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	r.users[user.ID] = cloneUser(*user)
+	return nil
+}
+
+func (r *MemoryUserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	user = cloneUser(user)
+	return &user, nil
+}
+
+func (r *MemoryUserRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, user := range r.users {
+		if user.Username == username {
+			user = cloneUser(user)
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email != "" && user.Email == email {
+			user = cloneUser(user)
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryUserRepository) Count(ctx context.Context) (int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return int64(len(r.users)), nil
+}
+
+func (r *MemoryUserRepository) Update(ctx context.Context, user *models.User) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.users[user.ID]; !ok {
+		return ErrNotFound
+	}
+	for id, existing := range r.users {
+		if id != user.ID && existing.Username == user.Username {
+			return ErrDuplicate
+		}
+	}
+	r.users[user.ID] = cloneUser(*user)
+	return nil
+}
+
+func (r *MemoryUserRepository) UpdateLastLogin(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	user.LastLoginAt = &at
+	r.users[id] = user
+	return nil
+}
+
+// cloneUser copies the slice fields so callers cannot mutate stored data.
+func cloneUser(user models.User) models.User {
+	if user.RecoveryCodes != nil {
+		user.RecoveryCodes = append([]string(nil), user.RecoveryCodes...)
+	}
+	return user
+}
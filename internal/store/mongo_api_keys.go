@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"portfolio-backend/internal/database"
+	"portfolio-backend/internal/models"
+)
+
+type MongoAPIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoAPIKeyRepository(db *database.MongoDB) *MongoAPIKeyRepository {
+	return &MongoAPIKeyRepository{
+		collection: db.GetCollection("api_keys"),
+	}
+}
+
+func (r *MongoAPIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	if key.ID.IsZero() {
+		key.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, key)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *MongoAPIKeyRepository) FindAll(ctx context.Context) ([]models.APIKey, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []models.APIKey
+	if err = cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *MongoAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.collection.FindOne(ctx, bson.M{"prefix": prefix}).Decode(&key)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *MongoAPIKeyRepository) Revoke(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": at}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoAPIKeyRepository) UpdateLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_used_at": at}})
+	return err
+}
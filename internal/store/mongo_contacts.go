@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"portfolio-backend/internal/database"
+	"portfolio-backend/internal/models"
+)
+
+type MongoContactRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoContactRepository(db *database.MongoDB) *MongoContactRepository {
+	return &MongoContactRepository{
+		collection: db.GetCollection("contacts"),
+	}
+}
+
+func (r *MongoContactRepository) Create(ctx context.Context, contact *models.Contact) error {
+	if contact.ID.IsZero() {
+		contact.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, contact)
+	return err
+}
+
+func (r *MongoContactRepository) Find(ctx context.Context, query ContactQuery) ([]models.Contact, error) {
+	filter := contactFilter(query)
+	sortBy := contactSortField(query.SortBy)
+
+	if query.After != nil {
+		value, err := parseCursorValue(query.After, ContactSortValue(&models.Contact{}, sortBy))
+		if err != nil {
+			return nil, err
+		}
+		filter = bson.M{"$and": bson.A{filter, keysetFilter(sortBy, value, query.After.ID, query.SortAsc)}}
+	}
+
+	direction := -1
+	if query.SortAsc {
+		direction = 1
+	}
+	opts := options.Find().SetSort(bson.D{{Key: sortBy, Value: direction}, {Key: "_id", Value: direction}})
+	if query.After == nil && query.Skip > 0 {
+		opts.SetSkip(query.Skip)
+	}
+	if query.Limit > 0 {
+		opts.SetLimit(query.Limit)
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var contacts []models.Contact
+	if err = cursor.All(ctx, &contacts); err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+func (r *MongoContactRepository) Count(ctx context.Context, query ContactQuery) (int64, error) {
+	return r.collection.CountDocuments(ctx, contactFilter(query))
+}
+
+func (r *MongoContactRepository) Search(ctx context.Context, text string, limit int64) ([]ScoredContact, error) {
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"$text": bson.M{"$search": text}, "deleted_at": nil}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []ScoredContact
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func contactFilter(query ContactQuery) bson.M {
+	filter := bson.M{"deleted_at": trashCondition(query.Trashed)}
+	if query.Read != nil {
+		filter["read"] = *query.Read
+	}
+	if r := timeRange(query.Since, query.Until); r != nil {
+		filter["created_at"] = r
+	}
+	if query.Email != "" {
+		filter["email"] = bson.M{"$regex": regexp.QuoteMeta(query.Email), "$options": "i"}
+	}
+	if query.Subject != "" {
+		filter["subject"] = bson.M{"$regex": regexp.QuoteMeta(query.Subject), "$options": "i"}
+	}
+	return filter
+}
+
+func (r *MongoContactRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Contact, error) {
+	var contact models.Contact
+	err := r.collection.FindOne(ctx, byID(id, false)).Decode(&contact)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &contact, nil
+}
+
+func (r *MongoContactRepository) MarkAsRead(ctx context.Context, id primitive.ObjectID, version int64) error {
+	result, err := r.collection.UpdateOne(ctx,
+		withVersion(byID(id, false), version),
+		bson.M{"$set": bson.M{"read": true, "version": version + 1}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return missingOrConflict(ctx, r.collection, byID(id, false))
+	}
+	return nil
+}
+
+func (r *MongoContactRepository) Trash(ctx context.Context, id primitive.ObjectID, version int64, deletedAt time.Time) error {
+	return trashDocument(ctx, r.collection, id, version, deletedAt)
+}
+
+func (r *MongoContactRepository) FindTrashedByID(ctx context.Context, id primitive.ObjectID) (*models.Contact, error) {
+	var contact models.Contact
+	err := r.collection.FindOne(ctx, byID(id, true)).Decode(&contact)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &contact, nil
+}
+
+func (r *MongoContactRepository) Restore(ctx context.Context, id primitive.ObjectID, version int64) error {
+	return restoreDocument(ctx, r.collection, id, version)
+}
+
+func (r *MongoContactRepository) Purge(ctx context.Context, id primitive.ObjectID, version int64) error {
+	return purgeDocument(ctx, r.collection, id, version)
+}
+
+func (r *MongoContactRepository) PurgeTrashed(ctx context.Context, cutoff time.Time) (int64, error) {
+	return purgeTrashed(ctx, r.collection, cutoff)
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"portfolio-backend/internal/database"
+	"portfolio-backend/internal/models"
+)
+
+type MongoLoginEventRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoLoginEventRepository(db *database.MongoDB) *MongoLoginEventRepository {
+	return &MongoLoginEventRepository{
+		collection: db.GetCollection("login_events"),
+	}
+}
+
+func (r *MongoLoginEventRepository) Create(ctx context.Context, event *models.LoginEvent) error {
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, event)
+	return err
+}
+
+func (r *MongoLoginEventRepository) Find(ctx context.Context, filter LoginEventFilter) ([]models.LoginEvent, error) {
+	query := bson.M{}
+	if filter.Username != "" {
+		query["username"] = filter.Username
+	}
+	if filter.IP != "" {
+		query["ip"] = filter.IP
+	}
+	if filter.Success != nil {
+		query["success"] = *filter.Success
+	}
+	createdAt := bson.M{}
+	if !filter.Since.IsZero() {
+		createdAt["$gte"] = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		createdAt["$lte"] = filter.Until
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []models.LoginEvent
+	if err = cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
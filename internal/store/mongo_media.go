@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"portfolio-backend/internal/database"
+	"portfolio-backend/internal/models"
+)
+
+type MongoMediaRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoMediaRepository(db *database.MongoDB) *MongoMediaRepository {
+	return &MongoMediaRepository{
+		collection: db.GetCollection("media"),
+	}
+}
+
+func (r *MongoMediaRepository) Create(ctx context.Context, media *models.Media) error {
+	if media.ID.IsZero() {
+		media.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, media)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *MongoMediaRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Media, error) {
+	var media models.Media
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&media)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &media, nil
+}
+
+func (r *MongoMediaRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Media, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.find(ctx, bson.M{"_id": bson.M{"$in": ids}}, options.Find())
+}
+
+func (r *MongoMediaRepository) Find(ctx context.Context, query MediaQuery) ([]models.Media, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(query.Skip)
+	if query.Limit > 0 {
+		opts.SetLimit(query.Limit)
+	}
+	return r.find(ctx, bson.M{}, opts)
+}
+
+func (r *MongoMediaRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.Media, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var media []models.Media
+	if err = cursor.All(ctx, &media); err != nil {
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func (r *MongoMediaRepository) Count(ctx context.Context) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{})
+}
+
+func (r *MongoMediaRepository) Update(ctx context.Context, media *models.Media) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": media.ID}, media)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoMediaRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
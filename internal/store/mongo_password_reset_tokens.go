@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"portfolio-backend/internal/database"
+	"portfolio-backend/internal/models"
+)
+
+type MongoPasswordResetTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoPasswordResetTokenRepository(db *database.MongoDB) *MongoPasswordResetTokenRepository {
+	return &MongoPasswordResetTokenRepository{
+		collection: db.GetCollection("password_reset_tokens"),
+	}
+}
+
+func (r *MongoPasswordResetTokenRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, token)
+	return err
+}
+
+func (r *MongoPasswordResetTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *MongoPasswordResetTokenRepository) MarkUsed(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "used_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"used_at": at}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *MongoPasswordResetTokenRepository) InvalidateForUser(ctx context.Context, username string, at time.Time) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"username": username, "used_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"used_at": at}},
+	)
+	return err
+}
@@ -0,0 +1,169 @@
+package store
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"portfolio-backend/internal/database"
+	"portfolio-backend/internal/models"
+)
+
+type MongoPostRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoPostRepository(db *database.MongoDB) *MongoPostRepository {
+	return &MongoPostRepository{
+		collection: db.GetCollection("posts"),
+	}
+}
+
+func (r *MongoPostRepository) Create(ctx context.Context, post *models.Post) error {
+	if post.ID.IsZero() {
+		post.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, post)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *MongoPostRepository) Find(ctx context.Context, query PostQuery) ([]models.Post, error) {
+	opts := options.Find().SetSort(bson.D{{Key: postSortField(query), Value: -1}, {Key: "_id", Value: -1}})
+	if query.Skip > 0 {
+		opts.SetSkip(query.Skip)
+	}
+	if query.Limit > 0 {
+		opts.SetLimit(query.Limit)
+	}
+
+	cursor, err := r.collection.Find(ctx, postFilter(query), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var posts []models.Post
+	if err = cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+func (r *MongoPostRepository) Count(ctx context.Context, query PostQuery) (int64, error) {
+	return r.collection.CountDocuments(ctx, postFilter(query))
+}
+
+func (r *MongoPostRepository) Tags(ctx context.Context, query PostQuery) ([]models.FacetCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: postFilter(query)}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tags []models.FacetCount
+	if err = cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func postFilter(query PostQuery) bson.M {
+	filter := bson.M{}
+	if query.Tag != "" {
+		filter["tags"] = query.Tag
+	}
+	if !query.PublicAt.IsZero() {
+		filter["published_at"] = bson.M{"$lte": query.PublicAt}
+	}
+	return filter
+}
+
+// postSortField orders public listings by publication and the others,
+// which include drafts, by creation.
+func postSortField(query PostQuery) string {
+	if !query.PublicAt.IsZero() {
+		return "published_at"
+	}
+	return "created_at"
+}
+
+func (r *MongoPostRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Post, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *MongoPostRepository) FindBySlug(ctx context.Context, slug string) (*models.Post, error) {
+	return r.findOne(ctx, bson.M{"slug": slug})
+}
+
+func (r *MongoPostRepository) findOne(ctx context.Context, filter bson.M) (*models.Post, error) {
+	var post models.Post
+	err := r.collection.FindOne(ctx, filter).Decode(&post)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &post, nil
+}
+
+func (r *MongoPostRepository) SlugTaken(ctx context.Context, slug string, except primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"slug": slug, "_id": bson.M{"$ne": except}})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *MongoPostRepository) UsesMedia(ctx context.Context, mediaID primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"cover_media_id": mediaID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *MongoPostRepository) Replace(ctx context.Context, post *models.Post) error {
+	version := post.Version
+	post.Version++
+
+	result, err := r.collection.ReplaceOne(ctx, withVersion(bson.M{"_id": post.ID}, version), post)
+	if err != nil {
+		post.Version = version
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return err
+	}
+	if result.MatchedCount == 0 {
+		post.Version = version
+		return missingOrConflict(ctx, r.collection, bson.M{"_id": post.ID})
+	}
+	return nil
+}
+
+func (r *MongoPostRepository) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	result, err := r.collection.DeleteOne(ctx, withVersion(bson.M{"_id": id}, version))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return missingOrConflict(ctx, r.collection, bson.M{"_id": id})
+	}
+	return nil
+}
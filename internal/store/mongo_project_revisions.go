@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"portfolio-backend/internal/database"
+	"portfolio-backend/internal/models"
+)
+
+type MongoProjectRevisionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoProjectRevisionRepository(db *database.MongoDB) *MongoProjectRevisionRepository {
+	return &MongoProjectRevisionRepository{
+		collection: db.GetCollection("project_revisions"),
+	}
+}
+
+func (r *MongoProjectRevisionRepository) Create(ctx context.Context, revision *models.ProjectRevision) error {
+	if revision.ID.IsZero() {
+		revision.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, revision)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *MongoProjectRevisionRepository) FindByProject(ctx context.Context, projectID primitive.ObjectID) ([]models.ProjectRevision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "number", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"project_id": projectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []models.ProjectRevision{}
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (r *MongoProjectRevisionRepository) FindByNumber(ctx context.Context, projectID primitive.ObjectID, number int64) (*models.ProjectRevision, error) {
+	var revision models.ProjectRevision
+	err := r.collection.FindOne(ctx, bson.M{"project_id": projectID, "number": number}).Decode(&revision)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
@@ -0,0 +1,321 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"portfolio-backend/internal/database"
+	"portfolio-backend/internal/models"
+)
+
+type MongoProjectRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoProjectRepository(db *database.MongoDB) *MongoProjectRepository {
+	return &MongoProjectRepository{
+		collection: db.GetCollection("projects"),
+	}
+}
+
+func (r *MongoProjectRepository) Create(ctx context.Context, project *models.Project) error {
+	if project.ID.IsZero() {
+		project.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, project)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *MongoProjectRepository) Find(ctx context.Context, query ProjectQuery) ([]models.Project, error) {
+	filter := projectFilter(query)
+	sortBy := projectSortField(query.SortBy)
+
+	if query.After != nil {
+		value, err := parseCursorValue(query.After, ProjectSortValue(&models.Project{}, sortBy))
+		if err != nil {
+			return nil, err
+		}
+		filter = bson.M{"$and": bson.A{filter, keysetFilter(sortBy, value, query.After.ID, query.SortAsc)}}
+	}
+
+	direction := -1
+	if query.SortAsc {
+		direction = 1
+	}
+	opts := options.Find().SetSort(bson.D{{Key: sortBy, Value: direction}, {Key: "_id", Value: direction}})
+	if query.After == nil && query.Skip > 0 {
+		opts.SetSkip(query.Skip)
+	}
+	if query.Limit > 0 {
+		opts.SetLimit(query.Limit)
+	}
+
+	return r.findWithOptions(ctx, filter, opts)
+}
+
+func (r *MongoProjectRepository) Count(ctx context.Context, query ProjectQuery) (int64, error) {
+	return r.collection.CountDocuments(ctx, projectFilter(query))
+}
+
+func (r *MongoProjectRepository) Facets(ctx context.Context, query ProjectQuery) (*models.ProjectFacets, error) {
+	countBy := func(field string) bson.A {
+		return bson.A{
+			bson.M{"$group": bson.M{"_id": field, "count": bson.M{"$sum": 1}}},
+			bson.M{"$match": bson.M{"_id": bson.M{"$nin": bson.A{nil, ""}}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: projectFilter(query)}},
+		{{Key: "$facet", Value: bson.M{
+			"categories": countBy("$category"),
+			"technologies": append(bson.A{bson.M{"$unwind": "$technologies"}},
+				countBy("$technologies")...),
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []models.ProjectFacets
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	facets := &models.ProjectFacets{}
+	if len(results) > 0 {
+		facets = &results[0]
+	}
+	return facets, nil
+}
+
+func (r *MongoProjectRepository) Search(ctx context.Context, text string, query ProjectQuery) ([]ScoredProject, error) {
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}})
+	if query.Limit > 0 {
+		opts.SetLimit(query.Limit)
+	}
+
+	filter := projectFilter(query)
+	filter["$text"] = bson.M{"$search": text}
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []ScoredProject
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func projectFilter(query ProjectQuery) bson.M {
+	filter := bson.M{"deleted_at": trashCondition(query.Trashed)}
+	if query.Category != "" {
+		filter["category"] = query.Category
+	}
+	if len(query.Technologies) > 0 {
+		op := "$in"
+		if query.AllTechnologies {
+			op = "$all"
+		}
+		filter["technologies"] = bson.M{op: query.Technologies}
+	}
+	if query.Featured != nil {
+		filter["featured"] = *query.Featured
+	}
+	if query.Status != "" {
+		filter["status"] = query.Status
+	}
+	if !query.PublicAt.IsZero() {
+		filter["status"] = models.ProjectStatusPublished
+		filter["$and"] = bson.A{
+			bson.M{"$or": bson.A{bson.M{"publish_at": nil}, bson.M{"publish_at": bson.M{"$lte": query.PublicAt}}}},
+			bson.M{"$or": bson.A{bson.M{"unpublish_at": nil}, bson.M{"unpublish_at": bson.M{"$gt": query.PublicAt}}}},
+		}
+	}
+	if !query.PublishDue.IsZero() {
+		filter["publish_at"] = bson.M{"$lte": query.PublishDue}
+	}
+	if !query.UnpublishDue.IsZero() {
+		filter["unpublish_at"] = bson.M{"$lte": query.UnpublishDue}
+	}
+	if r := timeRange(query.CreatedSince, query.CreatedUntil); r != nil {
+		filter["created_at"] = r
+	}
+	if r := timeRange(query.UpdatedSince, query.UpdatedUntil); r != nil {
+		filter["updated_at"] = r
+	}
+	return filter
+}
+
+func (r *MongoProjectRepository) findWithOptions(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.Project, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var projects []models.Project
+	if err = cursor.All(ctx, &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (r *MongoProjectRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Project, error) {
+	var project models.Project
+	err := r.collection.FindOne(ctx, byID(id, false)).Decode(&project)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+func (r *MongoProjectRepository) FindBySlug(ctx context.Context, slug string) (*models.Project, error) {
+	return r.findOne(ctx, bson.M{"slug": slug, "deleted_at": nil}, nil)
+}
+
+func (r *MongoProjectRepository) FindByPreviousSlug(ctx context.Context, slug string) (*models.Project, error) {
+	return r.findOne(ctx, bson.M{"previous_slugs": slug, "deleted_at": nil},
+		options.FindOne().SetSort(bson.D{{Key: "updated_at", Value: -1}}))
+}
+
+func (r *MongoProjectRepository) findOne(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*models.Project, error) {
+	if opts == nil {
+		opts = options.FindOne()
+	}
+
+	var project models.Project
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&project)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+func (r *MongoProjectRepository) SlugTaken(ctx context.Context, slug string, except primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"slug": slug, "_id": bson.M{"$ne": except}})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *MongoProjectRepository) UsesMedia(ctx context.Context, mediaID primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"media_ids": mediaID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *MongoProjectRepository) Replace(ctx context.Context, project *models.Project) error {
+	version := project.Version
+	project.Version++
+
+	result, err := r.collection.ReplaceOne(ctx, withVersion(byID(project.ID, false), version), project)
+	if err != nil {
+		project.Version = version
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return err
+	}
+	if result.MatchedCount == 0 {
+		project.Version = version
+		return missingOrConflict(ctx, r.collection, byID(project.ID, false))
+	}
+	return nil
+}
+
+// ReplaceMany runs the replacements in a transaction, which needs MongoDB
+// to run as a replica set (as Atlas does).
+func (r *MongoProjectRepository) ReplaceMany(ctx context.Context, projects []*models.Project) error {
+	session, err := r.collection.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	versions := make([]int64, len(projects))
+	for i, project := range projects {
+		versions[i] = project.Version
+	}
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		for i, project := range projects {
+			project.Version = versions[i] + 1
+			result, err := r.collection.ReplaceOne(sc, withVersion(byID(project.ID, false), versions[i]), project)
+			if err != nil {
+				if mongo.IsDuplicateKeyError(err) {
+					return nil, ErrDuplicate
+				}
+				return nil, err
+			}
+			if result.MatchedCount == 0 {
+				return nil, missingOrConflict(sc, r.collection, byID(project.ID, false))
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		for i, project := range projects {
+			project.Version = versions[i]
+		}
+	}
+	return err
+}
+
+func (r *MongoProjectRepository) Trash(ctx context.Context, id primitive.ObjectID, version int64, deletedAt time.Time) error {
+	return trashDocument(ctx, r.collection, id, version, deletedAt)
+}
+
+func (r *MongoProjectRepository) FindTrashedByID(ctx context.Context, id primitive.ObjectID) (*models.Project, error) {
+	var project models.Project
+	err := r.collection.FindOne(ctx, byID(id, true)).Decode(&project)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+func (r *MongoProjectRepository) Restore(ctx context.Context, id primitive.ObjectID, version int64) error {
+	return restoreDocument(ctx, r.collection, id, version)
+}
+
+func (r *MongoProjectRepository) Purge(ctx context.Context, id primitive.ObjectID, version int64) error {
+	return purgeDocument(ctx, r.collection, id, version)
+}
+
+func (r *MongoProjectRepository) PurgeTrashed(ctx context.Context, cutoff time.Time) (int64, error) {
+	return purgeTrashed(ctx, r.collection, cutoff)
+}
@@ -0,0 +1,300 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"portfolio-backend/internal/database"
+)
+
+// Schema change actions reported by EnsureSchema.
+const (
+	SchemaCreate     = "create"
+	SchemaUpdate     = "update"
+	SchemaRecreate   = "recreate"
+	SchemaUnexpected = "unexpected"
+)
+
+// SchemaChange is one difference between Schema and the database. Applied is
+// false when EnsureSchema only checked, and always false for unexpected
+// indexes, which are reported but never dropped.
+type SchemaChange struct {
+	Collection string
+	Object     string // collection, validator or index
+	Name       string
+	Action     string
+	Applied    bool
+}
+
+func (c SchemaChange) String() string {
+	status := "pending"
+	if c.Applied {
+		status = "applied"
+	}
+	if c.Action == SchemaUnexpected {
+		status = "not managed"
+	}
+	return fmt.Sprintf("%s: %s %s %s (%s)", c.Collection, c.Action, c.Object, c.Name, status)
+}
+
+// existingIndex is an index as returned by listIndexes.
+type existingIndex struct {
+	Name               string   `bson:"name"`
+	Key                bson.D   `bson:"key"`
+	Unique             bool     `bson:"unique"`
+	Sparse             bool     `bson:"sparse"`
+	ExpireAfterSeconds *float64 `bson:"expireAfterSeconds"`
+	Weights            bson.M   `bson:"weights"`
+}
+
+// EnsureSchema compares the database with Schema and reports the
+// differences. When apply is true, missing collections and indexes are
+// created, validators are updated and indexes with different options are
+// dropped and recreated.
+func EnsureSchema(ctx context.Context, db *database.MongoDB, apply bool) ([]SchemaChange, error) {
+	names, err := db.Database.ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(names))
+	for _, name := range names {
+		existing[name] = true
+	}
+
+	var changes []SchemaChange
+	for _, spec := range Schema {
+		collectionChanges, err := ensureCollection(ctx, db.Database, spec, existing[spec.Name], apply)
+		changes = append(changes, collectionChanges...)
+		if err != nil {
+			return changes, fmt.Errorf("%s: %w", spec.Name, err)
+		}
+	}
+	return changes, nil
+}
+
+func ensureCollection(ctx context.Context, db *mongo.Database, spec CollectionSpec, exists, apply bool) ([]SchemaChange, error) {
+	var changes []SchemaChange
+	change := func(object, name, action string) {
+		changes = append(changes, SchemaChange{
+			Collection: spec.Name,
+			Object:     object,
+			Name:       name,
+			Action:     action,
+			Applied:    apply && action != SchemaUnexpected,
+		})
+	}
+
+	if !exists {
+		change("collection", "", SchemaCreate)
+		if !apply {
+			// Everything else would be created along with the collection.
+			for _, index := range spec.Indexes {
+				change("index", index.name(), SchemaCreate)
+			}
+			return changes, nil
+		}
+		opts := options.CreateCollection()
+		if spec.Validator != nil {
+			opts.SetValidator(spec.Validator)
+		}
+		if err := db.CreateCollection(ctx, spec.Name, opts); err != nil {
+			return changes, err
+		}
+	} else if spec.Validator != nil {
+		current, err := currentValidator(ctx, db, spec.Name)
+		if err != nil {
+			return changes, err
+		}
+		if !sameDocument(current, spec.Validator) {
+			change("validator", "", SchemaUpdate)
+			if apply {
+				cmd := bson.D{{Key: "collMod", Value: spec.Name}, {Key: "validator", Value: spec.Validator}}
+				if err := db.RunCommand(ctx, cmd).Err(); err != nil {
+					return changes, err
+				}
+			}
+		}
+	}
+
+	indexes := db.Collection(spec.Name).Indexes()
+	current, err := listIndexes(ctx, indexes)
+	if err != nil {
+		return changes, err
+	}
+
+	managed := make(map[string]bool)
+	for _, index := range spec.Indexes {
+		found, ok := current[index.name()]
+		if !ok {
+			// Text indexes may exist under another name; a collection can only
+			// have one.
+			for _, candidate := range current {
+				if index.matches(candidate) {
+					found, ok = candidate, true
+					break
+				}
+			}
+		}
+
+		switch {
+		case !ok:
+			change("index", index.name(), SchemaCreate)
+		case index.matches(found):
+			managed[found.Name] = true
+			continue
+		default:
+			managed[found.Name] = true
+			change("index", index.name(), SchemaRecreate)
+			if apply {
+				if _, err := indexes.DropOne(ctx, found.Name); err != nil {
+					return changes, err
+				}
+			}
+		}
+		if apply {
+			if _, err := indexes.CreateOne(ctx, index.model()); err != nil {
+				return changes, err
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(current) {
+		if name != "_id_" && !managed[name] {
+			change("index", name, SchemaUnexpected)
+		}
+	}
+	return changes, nil
+}
+
+func currentValidator(ctx context.Context, db *mongo.Database, name string) (bson.M, error) {
+	specs, err := db.ListCollectionSpecifications(ctx, bson.M{"name": name})
+	if err != nil || len(specs) == 0 {
+		return nil, err
+	}
+
+	var collOptions struct {
+		Validator bson.M `bson:"validator"`
+	}
+	if err := bson.Unmarshal(specs[0].Options, &collOptions); err != nil {
+		return nil, err
+	}
+	return collOptions.Validator, nil
+}
+
+func listIndexes(ctx context.Context, indexes mongo.IndexView) (map[string]existingIndex, error) {
+	cursor, err := indexes.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var list []existingIndex
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]existingIndex, len(list))
+	for _, index := range list {
+		byName[index.Name] = index
+	}
+	return byName, nil
+}
+
+func (s IndexSpec) name() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	parts := make([]string, 0, len(s.Keys))
+	for _, key := range s.Keys {
+		parts = append(parts, fmt.Sprintf("%s_%v", key.Key, key.Value))
+	}
+	return strings.Join(parts, "_")
+}
+
+func (s IndexSpec) model() mongo.IndexModel {
+	opts := options.Index().SetName(s.name())
+	if s.Unique {
+		opts.SetUnique(true)
+	}
+	if s.Sparse {
+		opts.SetSparse(true)
+	}
+	if s.TTL {
+		opts.SetExpireAfterSeconds(0)
+	}
+	if s.Weights != nil {
+		opts.SetWeights(s.Weights)
+	}
+	return mongo.IndexModel{Keys: s.Keys, Options: opts}
+}
+
+// matches reports whether an existing index has the keys and options of s.
+func (s IndexSpec) matches(index existingIndex) bool {
+	if index.Unique != s.Unique || index.Sparse != s.Sparse {
+		return false
+	}
+	hasTTL := index.ExpireAfterSeconds != nil && *index.ExpireAfterSeconds == 0
+	if hasTTL != s.TTL {
+		return false
+	}
+
+	if s.Weights != nil {
+		// Text index keys are stored as _fts/_ftsx; compare the weights.
+		weights := make(bson.M, len(s.Weights))
+		for field, weight := range s.Weights {
+			weights[field] = weight
+		}
+		return index.Weights != nil && sameDocument(index.Weights, weights)
+	}
+
+	if len(index.Key) != len(s.Keys) {
+		return false
+	}
+	for i, key := range s.Keys {
+		if index.Key[i].Key != key.Key || fmt.Sprint(index.Key[i].Value) != fmt.Sprint(key.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameDocument compares two documents regardless of key order and numeric
+// type.
+func sameDocument(a, b bson.M) bool {
+	return bytes.Equal(canonicalJSON(a), canonicalJSON(b))
+}
+
+func canonicalJSON(doc bson.M) []byte {
+	if doc == nil {
+		return nil
+	}
+	// Round-trip through BSON so nested values have uniform types, then rely
+	// on encoding/json sorting map keys.
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return nil
+	}
+	var normalized bson.M
+	if err := bson.Unmarshal(data, &normalized); err != nil {
+		return nil
+	}
+	out, _ := json.Marshal(normalized)
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"portfolio-backend/internal/database"
+	"portfolio-backend/internal/models"
+)
+
+type MongoRefreshTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRefreshTokenRepository(db *database.MongoDB) *MongoRefreshTokenRepository {
+	return &MongoRefreshTokenRepository{
+		collection: db.GetCollection("refresh_tokens"),
+	}
+}
+
+func (r *MongoRefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, token)
+	return err
+}
+
+func (r *MongoRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *MongoRefreshTokenRepository) MarkRotated(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error) {
+	// Only the first caller wins, so a token can never be rotated twice
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "rotated_at": bson.M{"$exists": false}, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"rotated_at": at}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *MongoRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"family_id": familyID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": at}},
+	)
+	return err
+}
+
+func (r *MongoRefreshTokenRepository) RevokeAllForUser(ctx context.Context, username string, at time.Time) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"username": username, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": at}},
+	)
+	return err
+}
+
+type MongoRevokedTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRevokedTokenRepository(db *database.MongoDB) *MongoRevokedTokenRepository {
+	return &MongoRevokedTokenRepository{
+		collection: db.GetCollection("revoked_tokens"),
+	}
+}
+
+func (r *MongoRevokedTokenRepository) Add(ctx context.Context, token *models.RevokedToken) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": token.JTI}, token, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRevokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": jti}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the last item of a page for keyset pagination: the value of
+// the sort field and the id, which breaks ties between equal values.
+type Cursor struct {
+	Value string             `json:"v"`
+	ID    primitive.ObjectID `json:"id"`
+}
+
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(encoded string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// CursorValue formats a sort field value for a Cursor.
+func CursorValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// parseCursorValue converts a cursor value back into the type of the sort
+// field, given by like, so it compares correctly against stored documents.
+func parseCursorValue(cursor *Cursor, like interface{}) (interface{}, error) {
+	switch like.(type) {
+	case time.Time:
+		t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return t, nil
+	case float64:
+		f, err := strconv.ParseFloat(cursor.Value, 64)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return f, nil
+	default:
+		return cursor.Value, nil
+	}
+}
+
+// keysetFilter builds the Mongo condition selecting documents after the
+// cursor in the given sort order.
+func keysetFilter(field string, value interface{}, id primitive.ObjectID, asc bool) bson.M {
+	op := "$lt"
+	if asc {
+		op = "$gt"
+	}
+	return bson.M{
+		"$or": bson.A{
+			bson.M{field: bson.M{op: value}},
+			bson.M{field: value, "_id": bson.M{op: id}},
+		},
+	}
+}
+
+// compareValues orders two sort values of the same type.
+func compareValues(a, b interface{}) int {
+	switch av := a.(type) {
+	case time.Time:
+		return av.Compare(b.(time.Time))
+	case string:
+		return strings.Compare(av, b.(string))
+	case float64:
+		return cmp.Compare(av, b.(float64))
+	default:
+		return 0
+	}
+}
+
+// compareKeys orders two items by sort value and then id, honouring the
+// sort direction.
+func compareKeys(aValue interface{}, aID primitive.ObjectID, bValue interface{}, bID primitive.ObjectID, asc bool) int {
+	c := compareValues(aValue, bValue)
+	if c == 0 {
+		c = strings.Compare(aID.Hex(), bID.Hex())
+	}
+	if !asc {
+		c = -c
+	}
+	return c
+}
+
+// paginate returns up to limit items starting at start; a limit of 0 means
+// no limit.
+func paginate[T any](items []T, start int, limit int64) []T {
+	items = items[min(max(start, 0), len(items)):]
+	if limit > 0 && int64(len(items)) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// timeRange builds an inclusive Mongo range condition, or nil when both
+// bounds are zero.
+func timeRange(since, until time.Time) bson.M {
+	r := bson.M{}
+	if !since.IsZero() {
+		r["$gte"] = since
+	}
+	if !until.IsZero() {
+		r["$lte"] = until
+	}
+	if len(r) == 0 {
+		return nil
+	}
+	return r
+}
+
+// inTimeRange is the in-memory counterpart of timeRange.
+func inTimeRange(t, since, until time.Time) bool {
+	return (since.IsZero() || !t.Before(since)) && (until.IsZero() || !t.After(until))
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
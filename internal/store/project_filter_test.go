@@ -0,0 +1,222 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/internal/models"
+)
+
+// roundTrip marshals v to BSON and back, so documents and filters hold the
+// types MongoDB would compare.
+func roundTrip(t *testing.T, v interface{}) bson.M {
+	t.Helper()
+	data, err := bson.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m bson.M
+	if err := bson.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// evalFilter evaluates against doc the subset of the MongoDB query language
+// that projectFilter uses.
+func evalFilter(t *testing.T, doc, filter bson.M) bool {
+	t.Helper()
+	for key, cond := range filter {
+		switch key {
+		case "$and":
+			for _, clause := range cond.(bson.A) {
+				if !evalFilter(t, doc, clause.(bson.M)) {
+					return false
+				}
+			}
+		case "$or":
+			matched := false
+			for _, clause := range cond.(bson.A) {
+				matched = matched || evalFilter(t, doc, clause.(bson.M))
+			}
+			if !matched {
+				return false
+			}
+		default:
+			value, present := doc[key]
+			if !evalCondition(t, value, present, cond) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func evalCondition(t *testing.T, value interface{}, present bool, cond interface{}) bool {
+	t.Helper()
+	ops, ok := cond.(bson.M)
+	if !ok {
+		return valueEquals(value, present, cond)
+	}
+	for op, arg := range ops {
+		switch op {
+		case "$ne":
+			if valueEquals(value, present, arg) {
+				return false
+			}
+		case "$in":
+			matched := false
+			for _, want := range arg.(bson.A) {
+				matched = matched || valueEquals(value, present, want)
+			}
+			if !matched {
+				return false
+			}
+		case "$all":
+			for _, want := range arg.(bson.A) {
+				if !valueEquals(value, present, want) {
+					return false
+				}
+			}
+		case "$lt", "$lte", "$gt", "$gte":
+			if !present || value == nil || !compareDates(t, value, arg, op) {
+				return false
+			}
+		default:
+			t.Fatalf("unsupported operator %s", op)
+		}
+	}
+	return true
+}
+
+// valueEquals matches like MongoDB equality: null matches missing fields,
+// and arrays match when any of their elements does.
+func valueEquals(value interface{}, present bool, want interface{}) bool {
+	if want == nil {
+		return !present || value == nil
+	}
+	if array, ok := value.(bson.A); ok {
+		for _, item := range array {
+			if reflect.DeepEqual(item, want) {
+				return true
+			}
+		}
+		return false
+	}
+	return reflect.DeepEqual(value, want)
+}
+
+func compareDates(t *testing.T, value, arg interface{}, op string) bool {
+	t.Helper()
+	a, ok := value.(primitive.DateTime)
+	b, ok2 := arg.(primitive.DateTime)
+	if !ok || !ok2 {
+		t.Fatalf("cannot compare %T with %T", value, arg)
+	}
+	switch op {
+	case "$lt":
+		return a < b
+	case "$lte":
+		return a <= b
+	case "$gt":
+		return a > b
+	default:
+		return a >= b
+	}
+}
+
+// testProjects returns projects covering every combination of status and
+// schedule around now, with a spread of the other filtered fields.
+func testProjects(now time.Time) []models.Project {
+	at := func(hours int) *time.Time {
+		t := now.Add(time.Duration(hours) * time.Hour)
+		return &t
+	}
+	schedules := []struct{ publish, unpublish *time.Time }{
+		{nil, nil},
+		{at(-2), nil},
+		{at(0), nil},
+		{at(2), nil},
+		{nil, at(-1)},
+		{nil, at(0)},
+		{nil, at(1)},
+		{at(-2), at(2)},
+		{at(-2), at(-1)},
+	}
+	technologies := [][]string{nil, {"go"}, {"go", "react"}, {"react", "docker"}}
+	categories := []string{"web", "cli"}
+
+	var projects []models.Project
+	i := 0
+	for _, status := range models.ProjectStatuses {
+		for _, schedule := range schedules {
+			for _, deleted := range []bool{false, true} {
+				project := models.Project{
+					ID:           primitive.NewObjectID(),
+					Title:        "Project",
+					Status:       status,
+					PublishAt:    schedule.publish,
+					UnpublishAt:  schedule.unpublish,
+					Technologies: technologies[i%len(technologies)],
+					Category:     categories[i%len(categories)],
+					Featured:     i%3 == 0,
+					CreatedAt:    *at(-24 * (i % 5)),
+					UpdatedAt:    *at(-i % 7),
+				}
+				if deleted {
+					project.DeletedAt = at(-1)
+				}
+				projects = append(projects, project)
+				i++
+			}
+		}
+	}
+	return projects
+}
+
+func TestMatchProjectAgreesWithProjectFilter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	featured, notFeatured := true, false
+
+	queries := map[string]ProjectQuery{
+		"all":                 {},
+		"trashed":             {Trashed: true},
+		"category":            {Category: "web"},
+		"any technology":      {Technologies: []string{"go", "docker"}},
+		"all technologies":    {Technologies: []string{"go", "react"}, AllTechnologies: true},
+		"featured":            {Featured: &featured},
+		"not featured":        {Featured: &notFeatured},
+		"status":              {Status: models.ProjectStatusDraft},
+		"public":              {PublicAt: now},
+		"public in trash":     {PublicAt: now, Trashed: true},
+		"publish due":         {Status: models.ProjectStatusDraft, PublishDue: now},
+		"unpublish due":       {Status: models.ProjectStatusPublished, UnpublishDue: now},
+		"created since":       {CreatedSince: now.Add(-48 * time.Hour)},
+		"created until":       {CreatedUntil: now.Add(-48 * time.Hour)},
+		"updated in range":    {UpdatedSince: now.Add(-5 * time.Hour), UpdatedUntil: now.Add(-2 * time.Hour)},
+		"public web projects": {PublicAt: now, Category: "web", Technologies: []string{"go"}, Featured: &featured},
+	}
+
+	projects := testProjects(now)
+	for name, query := range queries {
+		filter := roundTrip(t, projectFilter(query))
+		matched := 0
+		for i := range projects {
+			project := &projects[i]
+			want := evalFilter(t, roundTrip(t, project), filter)
+			if got := matchProject(project, query); got != want {
+				t.Errorf("%s: matchProject = %v, projectFilter = %v for %+v", name, got, want, *project)
+			}
+			if want {
+				matched++
+			}
+		}
+		if matched == 0 {
+			t.Errorf("%s: no project matches, the query is not exercised", name)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package store
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// IndexSpec declares an index the service relies on.
+type IndexSpec struct {
+	Keys bson.D
+	// Name defaults to the name MongoDB generates from the keys, so indexes
+	// created by hand or by the old init scripts are recognised.
+	Name   string
+	Unique bool
+	Sparse bool
+	// TTL makes documents expire at the date stored in the indexed field.
+	TTL bool
+	// Weights sets the field weights of a text index.
+	Weights map[string]int
+}
+
+// CollectionSpec declares a collection, its JSON-schema validator and its
+// indexes.
+type CollectionSpec struct {
+	Name      string
+	Validator bson.M
+	Indexes   []IndexSpec
+}
+
+// Schema is the MongoDB layout expected by the repositories.
+// EnsureSchema reconciles the database with it.
+var Schema = []CollectionSpec{
+	{
+		Name: "contacts",
+		Validator: jsonSchema([]string{"name", "email", "subject", "message"}, bson.M{
+			"name": property("string", "must be a string and is required"),
+			"email": bson.M{
+				"bsonType":    "string",
+				"pattern":     `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`,
+				"description": "must be a valid email address and is required",
+			},
+			"subject":    property("string", "must be a string and is required"),
+			"message":    property("string", "must be a string and is required"),
+			"created_at": property("date", "must be a date"),
+			"read":       property("bool", "must be a boolean"),
+			"deleted_at": property("date", "must be a date"),
+		}),
+		Indexes: []IndexSpec{
+			{Keys: bson.D{{Key: "created_at", Value: -1}}},
+			{Keys: bson.D{{Key: "deleted_at", Value: 1}}, Sparse: true},
+			{Keys: bson.D{{Key: "read", Value: 1}}},
+			{Keys: bson.D{{Key: "email", Value: 1}}},
+			textIndex("contacts_text", ContactTextWeights),
+		},
+	},
+	{
+		Name: "projects",
+		Validator: jsonSchema([]string{"title", "description"}, bson.M{
+			"title":       property("string", "must be a string and is required"),
+			"slug":        property("string", "must be a string"),
+			"description": property("string", "must be a string and is required"),
+			"image_url":   property("string", "must be a string"),
+			"live_url":    property("string", "must be a string"),
+			"github_url":  property("string", "must be a string"),
+
+			"description_html": property("string", "must be a string"),
+			"excerpt":          property("string", "must be a string"),
+			"reading_time":     property("number", "must be a number"),
+
+			"technologies": bson.M{
+				"bsonType":    bson.A{"array", "null"},
+				"items":       bson.M{"bsonType": "string"},
+				"description": "must be an array of strings",
+			},
+			"media_ids": bson.M{
+				"bsonType":    "array",
+				"items":       bson.M{"bsonType": "objectId"},
+				"description": "must be an array of media ids",
+			},
+			"category": property("string", "must be a string"),
+			"featured": property("bool", "must be a boolean"),
+			"position": property("number", "must be a number"),
+			"status": bson.M{
+				"enum":        bson.A{"draft", "published", "archived"},
+				"description": "must be draft, published or archived",
+			},
+			"publish_at":   property("date", "must be a date"),
+			"unpublish_at": property("date", "must be a date"),
+			"created_at":   property("date", "must be a date"),
+			"updated_at":   property("date", "must be a date"),
+			"deleted_at":   property("date", "must be a date"),
+		}),
+		Indexes: []IndexSpec{
+			{Keys: bson.D{{Key: "created_at", Value: -1}}},
+			{Keys: bson.D{{Key: "deleted_at", Value: 1}}, Sparse: true},
+			{Keys: bson.D{{Key: "slug", Value: 1}}, Unique: true, Sparse: true},
+			{Keys: bson.D{{Key: "previous_slugs", Value: 1}}},
+			{Keys: bson.D{{Key: "media_ids", Value: 1}}},
+			{Keys: bson.D{{Key: "featured", Value: 1}}},
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "publish_at", Value: 1}}},
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "unpublish_at", Value: 1}}},
+			{Keys: bson.D{{Key: "category", Value: 1}}},
+			{Keys: bson.D{{Key: "technologies", Value: 1}}},
+			textIndex("projects_text", ProjectTextWeights),
+		},
+	},
+	{
+		Name: "project_revisions",
+		Indexes: []IndexSpec{
+			{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "number", Value: -1}}, Unique: true},
+		},
+	},
+	{
+		Name: "posts",
+		Validator: jsonSchema([]string{"title", "slug", "body"}, bson.M{
+			"title":     property("string", "must be a string and is required"),
+			"slug":      property("string", "must be a string and is required"),
+			"body":      property("string", "must be a string and is required"),
+			"body_html": property("string", "must be a string"),
+			"excerpt":   property("string", "must be a string"),
+			"tags": bson.M{
+				"bsonType":    bson.A{"array", "null"},
+				"items":       bson.M{"bsonType": "string"},
+				"description": "must be an array of strings",
+			},
+			"cover_media_id": property("objectId", "must be a media id"),
+			"author":         property("string", "must be a string"),
+			"published_at":   property("date", "must be a date"),
+			"created_at":     property("date", "must be a date"),
+			"updated_at":     property("date", "must be a date"),
+		}),
+		Indexes: []IndexSpec{
+			{Keys: bson.D{{Key: "slug", Value: 1}}, Unique: true},
+			{Keys: bson.D{{Key: "published_at", Value: -1}}},
+			{Keys: bson.D{{Key: "tags", Value: 1}, {Key: "published_at", Value: -1}}},
+			{Keys: bson.D{{Key: "created_at", Value: -1}}},
+			{Keys: bson.D{{Key: "cover_media_id", Value: 1}}, Sparse: true},
+		},
+	},
+	{
+		Name: "media",
+		Indexes: []IndexSpec{
+			{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		},
+	},
+	{
+		Name: "users",
+		Indexes: []IndexSpec{
+			{Keys: bson.D{{Key: "username", Value: 1}}, Unique: true},
+			{Keys: bson.D{{Key: "email", Value: 1}}, Sparse: true},
+		},
+	},
+	{
+		Name: "refresh_tokens",
+		Indexes: []IndexSpec{
+			{Keys: bson.D{{Key: "token_hash", Value: 1}}, Unique: true},
+			{Keys: bson.D{{Key: "family_id", Value: 1}}},
+			{Keys: bson.D{{Key: "username", Value: 1}}},
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, TTL: true},
+		},
+	},
+	{
+		Name: "revoked_tokens",
+		Indexes: []IndexSpec{
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, TTL: true},
+		},
+	},
+	{
+		Name: "api_keys",
+		Indexes: []IndexSpec{
+			{Keys: bson.D{{Key: "prefix", Value: 1}}, Unique: true},
+		},
+	},
+	{
+		Name: "login_events",
+		Indexes: []IndexSpec{
+			{Keys: bson.D{{Key: "created_at", Value: -1}}},
+			{Keys: bson.D{{Key: "username", Value: 1}, {Key: "created_at", Value: -1}}},
+			{Keys: bson.D{{Key: "ip", Value: 1}, {Key: "created_at", Value: -1}}},
+		},
+	},
+	{
+		Name: "password_reset_tokens",
+		Indexes: []IndexSpec{
+			{Keys: bson.D{{Key: "token_hash", Value: 1}}, Unique: true},
+			{Keys: bson.D{{Key: "username", Value: 1}}},
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, TTL: true},
+		},
+	},
+}
+
+func jsonSchema(required []string, properties bson.M) bson.M {
+	return bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType":   "object",
+			"required":   required,
+			"properties": properties,
+		},
+	}
+}
+
+func property(bsonType, description string) bson.M {
+	return bson.M{"bsonType": bsonType, "description": description}
+}
+
+func textIndex(name string, weights map[string]int) IndexSpec {
+	keys := bson.D{}
+	for _, field := range sortedKeys(weights) {
+		keys = append(keys, bson.E{Key: field, Value: "text"})
+	}
+	return IndexSpec{Keys: keys, Name: name, Weights: weights}
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"strings"
+
+	"portfolio-backend/internal/models"
+	"portfolio-backend/internal/search"
+)
+
+// ProjectTextWeights and ContactTextWeights are the relevance weights of the
+// searchable fields. They are used for the MongoDB text indexes and the
+// in-memory search index alike.
+var (
+	ProjectTextWeights = map[string]int{"title": 10, "technologies": 5, "category": 3, "description": 1}
+	ContactTextWeights = map[string]int{"subject": 5, "name": 3, "email": 3, "message": 1}
+)
+
+// ScoredProject is a project search match and its relevance score.
+type ScoredProject struct {
+	models.Project `bson:",inline"`
+	Score          float64 `bson:"score"`
+}
+
+// ScoredContact is a contact search match and its relevance score.
+type ScoredContact struct {
+	models.Contact `bson:",inline"`
+	Score          float64 `bson:"score"`
+}
+
+func projectSearchFields(project *models.Project) []search.Field {
+	return []search.Field{
+		{Text: project.Title, Weight: float64(ProjectTextWeights["title"])},
+		{Text: strings.Join(project.Technologies, " "), Weight: float64(ProjectTextWeights["technologies"])},
+		{Text: project.Category, Weight: float64(ProjectTextWeights["category"])},
+		{Text: project.Description, Weight: float64(ProjectTextWeights["description"])},
+	}
+}
+
+func contactSearchFields(contact *models.Contact) []search.Field {
+	return []search.Field{
+		{Text: contact.Subject, Weight: float64(ContactTextWeights["subject"])},
+		{Text: contact.Name, Weight: float64(ContactTextWeights["name"])},
+		{Text: contact.Email, Weight: float64(ContactTextWeights["email"])},
+		{Text: contact.Message, Weight: float64(ContactTextWeights["message"])},
+	}
+}
@@ -0,0 +1,408 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"portfolio-backend/configs"
+	"portfolio-backend/internal/database"
+	"portfolio-backend/internal/models"
+)
+
+// Supported values for configs.Config.StorageBackend.
+const (
+	BackendMongoDB = "mongodb"
+	BackendMemory  = "memory"
+)
+
+var (
+	// ErrNotFound is returned by repositories when no document matches the given id.
+	ErrNotFound = errors.New("not found")
+	// ErrDuplicate is returned when a write would violate a uniqueness constraint.
+	ErrDuplicate = errors.New("duplicate key")
+	// ErrVersionConflict is returned when a document was changed since the
+	// version the caller read.
+	ErrVersionConflict = errors.New("version conflict")
+)
+
+// ContactQuery filters, sorts and pages contact listings. Zero values match
+// everything outside the trash. When After is set, results start after that
+// cursor and Skip is ignored.
+type ContactQuery struct {
+	Trashed bool // list the trash instead
+	Read    *bool
+	Since   time.Time
+	Until   time.Time
+	Email   string // case-insensitive substring
+	Subject string // case-insensitive substring
+	SortBy  string // one of TrashedContactSortFields; defaults to created_at
+	SortAsc bool
+	Skip    int64
+	Limit   int64
+	After   *Cursor
+}
+
+// ContactSortFields lists the fields contacts can be sorted by.
+var ContactSortFields = []string{"created_at", "name", "email", "subject"}
+
+// TrashedContactSortFields lists the fields the contact trash can be sorted
+// by.
+var TrashedContactSortFields = append(slices.Clone(ContactSortFields), "deleted_at")
+
+func contactSortField(field string) string {
+	for _, allowed := range TrashedContactSortFields {
+		if field == allowed {
+			return field
+		}
+	}
+	return "created_at"
+}
+
+// ContactSortValue returns the value of contact's sort field.
+func ContactSortValue(contact *models.Contact, field string) interface{} {
+	switch field {
+	case "name":
+		return contact.Name
+	case "email":
+		return contact.Email
+	case "subject":
+		return contact.Subject
+	case "deleted_at":
+		return deletedAt(contact.DeletedAt)
+	default:
+		return contact.CreatedAt
+	}
+}
+
+type ContactRepository interface {
+	Create(ctx context.Context, contact *models.Contact) error
+	Find(ctx context.Context, query ContactQuery) ([]models.Contact, error)
+	Count(ctx context.Context, query ContactQuery) (int64, error)
+	// Search returns contacts matching text, most relevant first.
+	Search(ctx context.Context, text string, limit int64) ([]ScoredContact, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Contact, error)
+	// MarkAsRead and Trash only apply when the stored version equals
+	// version, returning ErrVersionConflict otherwise, and increment it.
+	// Trashed contacts are hidden from every method above.
+	MarkAsRead(ctx context.Context, id primitive.ObjectID, version int64) error
+	Trash(ctx context.Context, id primitive.ObjectID, version int64, deletedAt time.Time) error
+
+	// FindTrashedByID, Restore and Purge only see trashed contacts. Restore
+	// takes a contact out of the trash and increments its version; Purge
+	// deletes it permanently.
+	FindTrashedByID(ctx context.Context, id primitive.ObjectID) (*models.Contact, error)
+	Restore(ctx context.Context, id primitive.ObjectID, version int64) error
+	Purge(ctx context.Context, id primitive.ObjectID, version int64) error
+	// PurgeTrashed permanently deletes contacts trashed before cutoff and
+	// returns how many there were.
+	PurgeTrashed(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// ProjectQuery filters, sorts and pages project listings. Zero values match
+// everything outside the trash. When After is set, results start after that
+// cursor and Skip is ignored.
+type ProjectQuery struct {
+	Trashed      bool // list the trash instead
+	Category     string
+	Technologies []string
+	// AllTechnologies requires every listed technology instead of any of them.
+	AllTechnologies bool
+	Featured        *bool
+	Status          string // one of models.ProjectStatuses
+	CreatedSince    time.Time
+	CreatedUntil    time.Time
+	UpdatedSince    time.Time
+	UpdatedUntil    time.Time
+	SortBy          string // one of TrashedProjectSortFields; defaults to created_at
+	SortAsc         bool
+	Skip            int64
+	Limit           int64
+	After           *Cursor
+
+	// PublicAt restricts results to projects public at that time; see
+	// models.Project.IsPublic.
+	PublicAt time.Time
+	// PublishDue and UnpublishDue match projects whose publish_at or
+	// unpublish_at is set and not after the given time.
+	PublishDue   time.Time
+	UnpublishDue time.Time
+}
+
+// ProjectSortFields lists the fields projects can be sorted by. position is
+// the manual order set by admins.
+var ProjectSortFields = []string{"created_at", "updated_at", "title", "position"}
+
+// TrashedProjectSortFields lists the fields the project trash can be sorted
+// by.
+var TrashedProjectSortFields = append(slices.Clone(ProjectSortFields), "deleted_at")
+
+func projectSortField(field string) string {
+	for _, allowed := range TrashedProjectSortFields {
+		if field == allowed {
+			return field
+		}
+	}
+	return "created_at"
+}
+
+// ProjectSortValue returns the value of project's sort field.
+func ProjectSortValue(project *models.Project, field string) interface{} {
+	switch field {
+	case "updated_at":
+		return project.UpdatedAt
+	case "title":
+		return project.Title
+	case "position":
+		return project.Position
+	case "deleted_at":
+		return deletedAt(project.DeletedAt)
+	default:
+		return project.CreatedAt
+	}
+}
+
+type ProjectRepository interface {
+	Create(ctx context.Context, project *models.Project) error
+	Find(ctx context.Context, query ProjectQuery) ([]models.Project, error)
+	Count(ctx context.Context, query ProjectQuery) (int64, error)
+	// Search returns projects matching text and the filters of query, most
+	// relevant first. Of the sorting and paging fields only Limit is used.
+	Search(ctx context.Context, text string, query ProjectQuery) ([]ScoredProject, error)
+	// Facets counts the projects matching the filters of query per category
+	// and per technology.
+	Facets(ctx context.Context, query ProjectQuery) (*models.ProjectFacets, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Project, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Project, error)
+	// FindByPreviousSlug returns the most recently updated project that
+	// used to have slug.
+	FindByPreviousSlug(ctx context.Context, slug string) (*models.Project, error)
+	// SlugTaken reports whether a project other than except, including a
+	// trashed one, has slug as its current slug.
+	SlugTaken(ctx context.Context, slug string, except primitive.ObjectID) (bool, error)
+	// UsesMedia reports whether any project, including a trashed one, has
+	// mediaID in its gallery.
+	UsesMedia(ctx context.Context, mediaID primitive.ObjectID) (bool, error)
+	// Create and Replace return ErrDuplicate when the slug is taken.
+	//
+	// Replace stores project if the stored version equals project.Version
+	// and increments project.Version; otherwise it returns
+	// ErrVersionConflict. Trash likewise requires the given version and
+	// increments it. Trashed projects are hidden from every method above.
+	Replace(ctx context.Context, project *models.Project) error
+	// ReplaceMany is Replace for several projects at once: either every
+	// project is stored or, on error, none is.
+	ReplaceMany(ctx context.Context, projects []*models.Project) error
+	Trash(ctx context.Context, id primitive.ObjectID, version int64, deletedAt time.Time) error
+
+	// FindTrashedByID, Restore and Purge only see trashed projects. Restore
+	// takes a project out of the trash and increments its version; Purge
+	// deletes it permanently.
+	FindTrashedByID(ctx context.Context, id primitive.ObjectID) (*models.Project, error)
+	Restore(ctx context.Context, id primitive.ObjectID, version int64) error
+	Purge(ctx context.Context, id primitive.ObjectID, version int64) error
+	// PurgeTrashed permanently deletes projects trashed before cutoff and
+	// returns how many there were.
+	PurgeTrashed(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// ProjectRevisionRepository stores the immutable revision history of
+// projects.
+type ProjectRevisionRepository interface {
+	Create(ctx context.Context, revision *models.ProjectRevision) error
+	// FindByProject returns a project's revisions, newest first.
+	FindByProject(ctx context.Context, projectID primitive.ObjectID) ([]models.ProjectRevision, error)
+	FindByNumber(ctx context.Context, projectID primitive.ObjectID, number int64) (*models.ProjectRevision, error)
+}
+
+// PostQuery filters and pages post listings. Zero values match every post.
+type PostQuery struct {
+	Tag string
+	// PublicAt restricts results to posts public at that time, ordered by
+	// published_at instead of created_at; see models.Post.IsPublic.
+	PublicAt time.Time
+	Skip     int64
+	Limit    int64
+}
+
+type PostRepository interface {
+	// Create and Replace return ErrDuplicate when the slug is taken.
+	Create(ctx context.Context, post *models.Post) error
+	// Find returns posts newest first.
+	Find(ctx context.Context, query PostQuery) ([]models.Post, error)
+	Count(ctx context.Context, query PostQuery) (int64, error)
+	// Tags counts the posts matching the filters of query per tag.
+	Tags(ctx context.Context, query PostQuery) ([]models.FacetCount, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Post, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Post, error)
+	// SlugTaken reports whether a post other than except has slug.
+	SlugTaken(ctx context.Context, slug string, except primitive.ObjectID) (bool, error)
+	// UsesMedia reports whether any post has mediaID as its cover.
+	UsesMedia(ctx context.Context, mediaID primitive.ObjectID) (bool, error)
+	// Replace stores post if the stored version equals post.Version and
+	// increments post.Version; otherwise it returns ErrVersionConflict.
+	// Delete likewise requires the given version.
+	Replace(ctx context.Context, post *models.Post) error
+	Delete(ctx context.Context, id primitive.ObjectID, version int64) error
+}
+
+// MediaQuery pages media listings, which are ordered newest first.
+type MediaQuery struct {
+	Skip  int64
+	Limit int64
+}
+
+type MediaRepository interface {
+	Create(ctx context.Context, media *models.Media) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Media, error)
+	// FindByIDs returns the media among ids that exist, in no particular
+	// order.
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Media, error)
+	Find(ctx context.Context, query MediaQuery) ([]models.Media, error)
+	Count(ctx context.Context) (int64, error)
+	Update(ctx context.Context, media *models.Media) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error)
+	FindByUsername(ctx context.Context, username string) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	Count(ctx context.Context) (int64, error)
+	Update(ctx context.Context, user *models.User) error
+	UpdateLastLogin(ctx context.Context, id primitive.ObjectID, at time.Time) error
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	// MarkRotated flags the token as used. It reports false if the token was
+	// already rotated or revoked.
+	MarkRotated(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+	RevokeAllForUser(ctx context.Context, username string, at time.Time) error
+}
+
+type RevokedTokenRepository interface {
+	Add(ctx context.Context, token *models.RevokedToken) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	FindAll(ctx context.Context) ([]models.APIKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	Revoke(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	UpdateLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error
+}
+
+// LoginEventFilter narrows a login audit query. Zero values match everything.
+type LoginEventFilter struct {
+	Username string
+	IP       string
+	Success  *bool
+	Since    time.Time
+	Until    time.Time
+	Limit    int64
+}
+
+type LoginEventRepository interface {
+	Create(ctx context.Context, event *models.LoginEvent) error
+	Find(ctx context.Context, filter LoginEventFilter) ([]models.LoginEvent, error)
+}
+
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
+	// MarkUsed flags the token as consumed. It reports false if it was
+	// already used.
+	MarkUsed(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error)
+	InvalidateForUser(ctx context.Context, username string, at time.Time) error
+}
+
+// Store bundles the repositories of one storage backend.
+type Store struct {
+	Contacts         ContactRepository
+	Projects         ProjectRepository
+	ProjectRevisions ProjectRevisionRepository
+	Posts            PostRepository
+	Media            MediaRepository
+	Users            UserRepository
+
+	RefreshTokens RefreshTokenRepository
+	RevokedTokens RevokedTokenRepository
+	APIKeys       APIKeyRepository
+	LoginEvents   LoginEventRepository
+	ResetTokens   PasswordResetTokenRepository
+
+	db *database.MongoDB
+}
+
+// New opens the storage backend selected by config.StorageBackend.
+func New(config *configs.Config) (*Store, error) {
+	switch config.StorageBackend {
+	case BackendMongoDB, "":
+		db, err := database.NewMongoDB(config.MongoDBURI, config.MongoDBDatabase)
+		if err != nil {
+			return nil, err
+		}
+		return NewMongoStore(db), nil
+	case BackendMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", config.StorageBackend)
+	}
+}
+
+func NewMongoStore(db *database.MongoDB) *Store {
+	return &Store{
+		Contacts:         NewMongoContactRepository(db),
+		Projects:         NewMongoProjectRepository(db),
+		ProjectRevisions: NewMongoProjectRevisionRepository(db),
+		Posts:            NewMongoPostRepository(db),
+		Media:            NewMongoMediaRepository(db),
+		Users:            NewMongoUserRepository(db),
+
+		RefreshTokens: NewMongoRefreshTokenRepository(db),
+		RevokedTokens: NewMongoRevokedTokenRepository(db),
+		APIKeys:       NewMongoAPIKeyRepository(db),
+		LoginEvents:   NewMongoLoginEventRepository(db),
+		ResetTokens:   NewMongoPasswordResetTokenRepository(db),
+
+		db: db,
+	}
+}
+
+func NewMemoryStore() *Store {
+	return &Store{
+		Contacts:         NewMemoryContactRepository(),
+		Projects:         NewMemoryProjectRepository(),
+		ProjectRevisions: NewMemoryProjectRevisionRepository(),
+		Posts:            NewMemoryPostRepository(),
+		Media:            NewMemoryMediaRepository(),
+		Users:            NewMemoryUserRepository(),
+
+		RefreshTokens: NewMemoryRefreshTokenRepository(),
+		RevokedTokens: NewMemoryRevokedTokenRepository(),
+		APIKeys:       NewMemoryAPIKeyRepository(),
+		LoginEvents:   NewMemoryLoginEventRepository(),
+		ResetTokens:   NewMemoryPasswordResetTokenRepository(),
+	}
+}
+
+// DB returns the underlying MongoDB connection, or nil for non-Mongo backends.
+func (s *Store) DB() *database.MongoDB {
+	return s.db
+}
+
+func (s *Store) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
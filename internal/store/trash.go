@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// deletedAt is the sort value of a trash marker; items outside the trash
+// sort as the zero time.
+func deletedAt(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// trashCondition matches documents in the trash, or outside it. Documents
+// written before soft deletes have no deleted_at field and are not trashed.
+func trashCondition(trashed bool) interface{} {
+	if trashed {
+		return bson.M{"$ne": nil}
+	}
+	return nil
+}
+
+// byID selects a document by id, inside or outside the trash.
+func byID(id primitive.ObjectID, trashed bool) bson.M {
+	return bson.M{"_id": id, "deleted_at": trashCondition(trashed)}
+}
+
+// The helpers below implement the trash operations shared by the Mongo
+// project and contact repositories.
+
+func trashDocument(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID, version int64, at time.Time) error {
+	result, err := collection.UpdateOne(ctx,
+		withVersion(byID(id, false), version),
+		bson.M{"$set": bson.M{"deleted_at": at, "version": version + 1}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return missingOrConflict(ctx, collection, byID(id, false))
+	}
+	return nil
+}
+
+func restoreDocument(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID, version int64) error {
+	result, err := collection.UpdateOne(ctx,
+		withVersion(byID(id, true), version),
+		bson.M{"$unset": bson.M{"deleted_at": ""}, "$set": bson.M{"version": version + 1}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return missingOrConflict(ctx, collection, byID(id, true))
+	}
+	return nil
+}
+
+func purgeDocument(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID, version int64) error {
+	result, err := collection.DeleteOne(ctx, withVersion(byID(id, true), version))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return missingOrConflict(ctx, collection, byID(id, true))
+	}
+	return nil
+}
+
+func purgeTrashed(ctx context.Context, collection *mongo.Collection, cutoff time.Time) (int64, error) {
+	result, err := collection.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// versionFilter matches documents at version. Documents written before
+// versioning have no version field and count as version 0.
+func versionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// missingOrConflict explains why a versioned write matched nothing: no
+// document matches filter, or it has a different version.
+func missingOrConflict(ctx context.Context, collection *mongo.Collection, filter bson.M) error {
+	count, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return ErrVersionConflict
+}
+
+// withVersion adds a versionFilter condition to filter.
+func withVersion(filter bson.M, version int64) bson.M {
+	filter["version"] = versionFilter(version)
+	return filter
+}